@@ -0,0 +1,92 @@
+package seatable_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// jwtRefreshMargin renews the access token a little before it expires
+// so that requests in flight don't race the expiry.
+const jwtRefreshMargin = 5 * time.Minute
+
+func (s *Base) dtableURL(path string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + path
+}
+
+func (s *Base) dtableServerURL() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.DtableServerURL
+}
+
+func (s *Base) dtableDbURL() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.DtableDbURL
+}
+
+func (s *Base) dtableUUID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.DtableUUID
+}
+
+func (s *Base) workspaceID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.WorkspaceID
+}
+
+func (s *Base) jwtToken() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.JwtToken
+}
+
+// authHeaders returns a copy of the headers for the current access token.
+func (s *Base) authHeaders() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	headers := make(map[string]string, len(s.Headers))
+	for k, v := range s.Headers {
+		headers[k] = v
+	}
+	return headers
+}
+
+func (s *Base) isJwtAuth(headers map[string]string) bool {
+	jwt := s.jwtToken()
+	return jwt != "" && headers["Authorization"] == "Token "+jwt
+}
+
+func (s *Base) jwtExpired() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return time.Now().Add(jwtRefreshMargin).Unix() >= s.JwtExp
+}
+
+// refreshAuth renews the access token used by req and updates its
+// Authorization header. Concurrent callers holding the same stale token
+// wait for a single call to the app-access-token endpoint.
+func (s *Base) refreshAuth(ctx context.Context, req *http.Request) error {
+	stale := strings.TrimPrefix(req.Header.Get("Authorization"), "Token ")
+
+	s.authMu.Lock()
+	defer s.authMu.Unlock()
+
+	if s.jwtToken() == stale || s.jwtExpired() {
+		if err := s.AuthCtx(ctx, false); err != nil {
+			err := fmt.Errorf("failed to refresh access token: %w", err)
+			return err
+		}
+	}
+
+	req.Header.Set("Authorization", "Token "+s.jwtToken())
+	return nil
+}
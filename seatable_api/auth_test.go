@@ -0,0 +1,77 @@
+package seatable_api
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRefreshAfterUnauthorized(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"rows": []interface{}{}})
+	})
+	b := si.base(t)
+
+	// Invalidate the token on the server side, as after its expiry.
+	si.revokeTokens()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := b.ListRows(tableName, ""); err != nil {
+				t.Errorf("failed to list rows: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := si.authCount() - 1; n != 1 {
+		t.Errorf("expected a single token refresh, got %d", n)
+	}
+}
+
+func TestRefreshBeforeExpiry(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"rows": []interface{}{}})
+	})
+
+	tests := []struct {
+		name      string
+		exp       time.Duration
+		refreshes int
+	}{
+		{"expired", -time.Hour, 1},
+		{"within margin", jwtRefreshMargin / 2, 1},
+		{"valid", 2 * jwtRefreshMargin, 0},
+	}
+
+	for _, tt := range tests {
+		b := si.base(t)
+		b.JwtExp = time.Now().Add(tt.exp).Unix()
+		before := si.authCount()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := b.ListRows(tableName, ""); err != nil {
+					t.Errorf("%s: failed to list rows: %v", tt.name, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if n := si.authCount() - before; n != tt.refreshes {
+			t.Errorf("%s: expected %d token refreshes, got %d", tt.name, tt.refreshes, n)
+		}
+		if tt.refreshes > 0 && b.jwtExpired() {
+			t.Errorf("%s: token still expired after refresh", tt.name)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package seatable_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// maxBatchRows is the number of rows dtable-server accepts in a single
+// batch request.
+const maxBatchRows = 1000
+
+// BatchOptions splits batch operations into several requests.
+type BatchOptions struct {
+	// ChunkSize is the number of rows per request, by default and at
+	// most 1000.
+	ChunkSize int
+	// Concurrency is the number of requests sent in parallel, by
+	// default 1.
+	Concurrency int
+}
+
+func (opts *BatchOptions) chunkSize() int {
+	if opts == nil || opts.ChunkSize <= 0 || opts.ChunkSize > maxBatchRows {
+		return maxBatchRows
+	}
+	return opts.ChunkSize
+}
+
+func (opts *BatchOptions) concurrency() int {
+	if opts == nil || opts.Concurrency <= 0 {
+		return 1
+	}
+	return opts.Concurrency
+}
+
+// BatchChunk is the result of the request for the input rows Start up
+// to End of a batch operation.
+type BatchChunk struct {
+	Start    int
+	End      int
+	Response map[string]interface{}
+	Err      error
+}
+
+// BatchResult is the result of a batch operation. Requests are
+// independent: a failed request does not stop the others, and the rows
+// of successful requests stay written.
+type BatchResult struct {
+	Chunks []*BatchChunk
+	// RowIDs holds the ids of appended rows in input order. Rows of
+	// failed requests have an empty id.
+	RowIDs []string
+}
+
+func (r *BatchResult) Succeeded() []*BatchChunk {
+	var ret []*BatchChunk
+	for _, c := range r.Chunks {
+		if c.Err == nil {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+func (r *BatchResult) Failed() []*BatchChunk {
+	var ret []*BatchChunk
+	for _, c := range r.Chunks {
+		if c.Err != nil {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// Err returns an error wrapping the error of the first failed request,
+// or nil if all requests succeeded.
+func (r *BatchResult) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	c := failed[0]
+	err := fmt.Errorf("%d of %d batch requests failed, rows %d to %d: %w", len(failed), len(r.Chunks), c.Start, c.End, c.Err)
+	return err
+}
+
+// Response merges the responses of the successful requests: numbers such
+// as inserted_row_count are added up and lists are concatenated.
+func (r *BatchResult) Response() map[string]interface{} {
+	ret := make(map[string]interface{})
+	for _, c := range r.Succeeded() {
+		for k, v := range c.Response {
+			switch v := v.(type) {
+			case float64:
+				n, _ := ret[k].(float64)
+				ret[k] = n + v
+			case []interface{}:
+				list, _ := ret[k].([]interface{})
+				ret[k] = append(list, v...)
+			default:
+				ret[k] = v
+			}
+		}
+	}
+	return ret
+}
+
+// runBatch calls fn for chunks of n rows, running opts.Concurrency calls
+// at a time.
+func runBatch(ctx context.Context, n int, opts *BatchOptions, fn func(ctx context.Context, start, end int) (map[string]interface{}, error)) *BatchResult {
+	ret := new(BatchResult)
+	size := opts.chunkSize()
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		ret.Chunks = append(ret.Chunks, &BatchChunk{Start: start, End: end})
+	}
+
+	sem := make(chan struct{}, opts.concurrency())
+	var wg sync.WaitGroup
+	for _, c := range ret.Chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			c.Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(c *BatchChunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.Response, c.Err = fn(ctx, c.Start, c.End)
+		}(c)
+	}
+	wg.Wait()
+	return ret
+}
+
+// BatchAppendRowsWithOptions appends rows in chunks and returns the ids
+// of the new rows in input order. The error is that of the result.
+func (s *Base) BatchAppendRowsWithOptions(tableName string, rowsData []interface{}, opts *BatchOptions) (*BatchResult, error) {
+	return s.BatchAppendRowsWithOptionsCtx(context.Background(), tableName, rowsData, opts)
+}
+
+func (s *Base) BatchAppendRowsWithOptionsCtx(ctx context.Context, tableName string, rowsData []interface{}, opts *BatchOptions) (*BatchResult, error) {
+	rows := make([]interface{}, 0, len(rowsData))
+	for _, v := range rowsData {
+		row, err := toRowData(v)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+
+	ret := runBatch(ctx, len(rows), opts, func(ctx context.Context, start, end int) (map[string]interface{}, error) {
+		return s.batchAppendRows(ctx, tableName, rows[start:end])
+	})
+
+	ret.RowIDs = make([]string, len(rows))
+	for _, c := range ret.Succeeded() {
+		ids, _ := c.Response["row_ids"].([]interface{})
+		if len(ids) != c.End-c.Start {
+			continue
+		}
+		for i, v := range ids {
+			switch id := v.(type) {
+			case string:
+				ret.RowIDs[c.Start+i] = id
+			case map[string]interface{}:
+				ret.RowIDs[c.Start+i], _ = id["_id"].(string)
+			}
+		}
+	}
+	return ret, ret.Err()
+}
+
+func (s *Base) BatchDeleteRowsWithOptions(tableName string, rowIDs []string, opts *BatchOptions) (*BatchResult, error) {
+	return s.BatchDeleteRowsWithOptionsCtx(context.Background(), tableName, rowIDs, opts)
+}
+
+func (s *Base) BatchDeleteRowsWithOptionsCtx(ctx context.Context, tableName string, rowIDs []string, opts *BatchOptions) (*BatchResult, error) {
+	ret := runBatch(ctx, len(rowIDs), opts, func(ctx context.Context, start, end int) (map[string]interface{}, error) {
+		return s.batchDeleteRows(ctx, tableName, rowIDs[start:end])
+	})
+	return ret, ret.Err()
+}
+
+func toRowIDs(v interface{}) ([]string, error) {
+	switch ids := v.(type) {
+	case []string:
+		return ids, nil
+	case []interface{}:
+		ret := make([]string, 0, len(ids))
+		for _, id := range ids {
+			s, ok := id.(string)
+			if !ok {
+				err := fmt.Errorf("row id %v is not a string", id)
+				return nil, err
+			}
+			ret = append(ret, s)
+		}
+		return ret, nil
+	}
+	err := fmt.Errorf("row ids must be a slice of strings, got %T", v)
+	return nil, err
+}
+
+// RowUpdate is a row update for BatchUpdateRows. Row is a map or a
+// tagged struct.
+type RowUpdate struct {
+	RowID string
+	Row   interface{}
+}
+
+// BatchUpdateRows updates rows in requests of at most 1000 rows and
+// returns the merged responses.
+func (s *Base) BatchUpdateRows(tableName string, updates []RowUpdate) (map[string]interface{}, error) {
+	return s.BatchUpdateRowsCtx(context.Background(), tableName, updates)
+}
+
+func (s *Base) BatchUpdateRowsCtx(ctx context.Context, tableName string, updates []RowUpdate) (map[string]interface{}, error) {
+	ret, err := s.BatchUpdateRowsWithOptionsCtx(ctx, tableName, updates, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ret.Response(), nil
+}
+
+func (s *Base) BatchUpdateRowsWithOptions(tableName string, updates []RowUpdate, opts *BatchOptions) (*BatchResult, error) {
+	return s.BatchUpdateRowsWithOptionsCtx(context.Background(), tableName, updates, opts)
+}
+
+func (s *Base) BatchUpdateRowsWithOptionsCtx(ctx context.Context, tableName string, updates []RowUpdate, opts *BatchOptions) (*BatchResult, error) {
+	list := make([]interface{}, 0, len(updates))
+	for _, u := range updates {
+		row, err := toRowData(u.Row)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, map[string]interface{}{"row_id": u.RowID, "row": row})
+	}
+
+	ret := runBatch(ctx, len(list), opts, func(ctx context.Context, start, end int) (map[string]interface{}, error) {
+		return s.batchUpdateRows(ctx, tableName, list[start:end])
+	})
+	return ret, ret.Err()
+}
+
+func (s *Base) batchUpdateRows(ctx context.Context, tableName string, updates []interface{}) (map[string]interface{}, error) {
+	url := s.dtableURL("/batch-update-rows/")
+
+	data := make(map[string]interface{})
+	data["table_name"] = tableName
+	data["updates"] = updates
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		err := fmt.Errorf("failed to encode put data: %v", err)
+		return nil, err
+	}
+
+	status, body, err := s.httpPut(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to post rows to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("PUT", url, status, body)
+		return nil, err
+	}
+
+	rsp, err := parseResponse(body)
+	if err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+
+	ret, ok := rsp.(map[string]interface{})
+	if !ok {
+		err := fmt.Errorf("failed to assert response")
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+type UpsertResult struct {
+	Updated  int
+	Appended int
+}
+
+// UpsertRows updates the rows whose keyColumn matches an existing row of
+// the table and appends the others. keyColumn is a column name or key, or
+// an internal column such as _id. Rows are maps or tagged structs and
+// must contain keyColumn unless they are to be appended; two rows with
+// the same key are an error. When several rows of the table share a key,
+// the first one is updated.
+func (s *Base) UpsertRows(tableName, keyColumn string, rowsData []interface{}) (*UpsertResult, error) {
+	return s.UpsertRowsCtx(context.Background(), tableName, keyColumn, rowsData)
+}
+
+func (s *Base) UpsertRowsCtx(ctx context.Context, tableName, keyColumn string, rowsData []interface{}) (*UpsertResult, error) {
+	// Rows may refer to the key column by name or key.
+	keys := []string{keyColumn}
+	if !strings.HasPrefix(keyColumn, "_") {
+		col, err := s.getColumn(ctx, tableName, keyColumn)
+		if err != nil {
+			return nil, err
+		}
+		keys = []string{col.Name, col.Key}
+	}
+
+	existing := make(map[string]string)
+	it := s.IterateRowsCtx(ctx, tableName, "", nil)
+	for it.Next() {
+		row := it.Row()
+		key, ok := upsertKey(rowCell(row.Values, keys))
+		if !ok {
+			continue
+		}
+		if _, ok := existing[key]; !ok {
+			existing[key] = row.ID
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	var updates []RowUpdate
+	var appends []interface{}
+	seen := make(map[string]int)
+	for i, v := range rowsData {
+		row, err := toRowData(v)
+		if err != nil {
+			return nil, err
+		}
+		values, ok := row.(map[string]interface{})
+		if !ok {
+			err := fmt.Errorf("can not upsert %T, expect a map or struct", v)
+			return nil, err
+		}
+
+		key, ok := upsertKey(upsertCell(v, values, keys))
+		if ok {
+			if j, dup := seen[key]; dup {
+				err := fmt.Errorf("rows %d and %d have the same key %q", j, i, key)
+				return nil, err
+			}
+			seen[key] = i
+		}
+		if rowID, found := existing[key]; ok && found {
+			updates = append(updates, RowUpdate{RowID: rowID, Row: values})
+		} else {
+			appends = append(appends, values)
+		}
+	}
+
+	ret := new(UpsertResult)
+	if len(updates) > 0 {
+		if _, err := s.BatchUpdateRowsCtx(ctx, tableName, updates); err != nil {
+			return nil, err
+		}
+		ret.Updated = len(updates)
+	}
+	if len(appends) > 0 {
+		if _, err := s.BatchAppendRowsCtx(ctx, tableName, appends); err != nil {
+			return ret, err
+		}
+		ret.Appended = len(appends)
+	}
+	return ret, nil
+}
+
+// rowCell returns the first of the cells keys found in values.
+func rowCell(values map[string]interface{}, keys []string) interface{} {
+	for _, k := range keys {
+		if v, ok := values[k]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// upsertCell returns the key cell of an input row. Struct fields are read
+// from the struct, as Marshal leaves out internal columns such as _id.
+func upsertCell(v interface{}, values map[string]interface{}, keys []string) interface{} {
+	rv, ok := structValue(v)
+	if !ok {
+		return rowCell(values, keys)
+	}
+	for _, f := range structFields(rv.Type()) {
+		for _, k := range keys {
+			if f.column == k {
+				return marshalValue(rv.Field(f.index), f.dateOnly)
+			}
+		}
+	}
+	return nil
+}
+
+// upsertKey returns the key of a cell value; empty cells have no key.
+func upsertKey(v interface{}) (string, bool) {
+	switch k := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return k, k != ""
+	}
+	return cellText(v), true
+}
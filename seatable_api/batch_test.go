@@ -0,0 +1,233 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchUpdateRows(t *testing.T) {
+	var sizes []int
+	si := newStandIn(t)
+	si.handle("/batch-update-rows/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			TableName string                   `json:"table_name"`
+			Updates   []map[string]interface{} `json:"updates"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.Method != http.MethodPut || req.TableName != "table1" {
+			t.Errorf("unexpected request %s %+v", r.Method, req)
+		}
+		sizes = append(sizes, len(req.Updates))
+		writeJSON(w, map[string]interface{}{"success": true})
+	})
+	b := si.base(t)
+
+	updates := make([]RowUpdate, 2500)
+	for i := range updates {
+		updates[i] = RowUpdate{RowID: fmt.Sprintf("row%d", i), Row: map[string]interface{}{"age": i}}
+	}
+	if _, err := b.BatchUpdateRows("table1", updates); err != nil {
+		t.Fatalf("batch update failed: %v", err)
+	}
+	if len(sizes) != 3 || sizes[0] != 1000 || sizes[2] != 500 {
+		t.Errorf("unexpected chunks %v", sizes)
+	}
+}
+
+func TestUpsertRows(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMetadata))
+	})
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"rows": []interface{}{
+			map[string]interface{}{"_id": "r1", "Name": "Ada", "age": 36},
+			map[string]interface{}{"_id": "r2", "Name": "Bob", "age": 17},
+		}})
+	})
+	var updated map[string]interface{}
+	si.handle("/batch-update-rows/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Updates []struct {
+				RowID string                 `json:"row_id"`
+				Row   map[string]interface{} `json:"row"`
+			} `json:"updates"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		updated = make(map[string]interface{})
+		for _, u := range req.Updates {
+			updated[u.RowID] = u.Row["age"]
+		}
+		writeJSON(w, map[string]interface{}{"success": true})
+	})
+	var appended []interface{}
+	si.handle("/batch-append-rows/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Rows []interface{} `json:"rows"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		appended = req.Rows
+		writeJSON(w, map[string]interface{}{"inserted_row_count": len(req.Rows)})
+	})
+	b := si.base(t)
+
+	type person struct {
+		Name string `seatable:"Name"`
+		Age  int    `seatable:"age"`
+	}
+	ret, err := b.UpsertRows("table1", "Name", []interface{}{
+		person{Name: "Bob", Age: 18},
+		map[string]interface{}{"Name": "Cy", "age": 52},
+		map[string]interface{}{"age": 1},
+	})
+	if err != nil {
+		t.Fatalf("upsert failed: %v", err)
+	}
+	if ret.Updated != 1 || ret.Appended != 2 {
+		t.Errorf("unexpected result %+v", ret)
+	}
+	if len(updated) != 1 || updated["r2"] != 18.0 || len(appended) != 2 {
+		t.Errorf("updated %v, appended %v", updated, appended)
+	}
+
+	// The key column may be given by key, rows may use either.
+	updated, appended = nil, nil
+	ret, err = b.UpsertRows("table1", "0000", []interface{}{
+		person{Name: "Ada", Age: 37},
+		map[string]interface{}{"0000": "Bob", "age": 19},
+	})
+	if err != nil || ret.Updated != 2 || ret.Appended != 0 {
+		t.Errorf("upsert by column key = %+v, %v", ret, err)
+	}
+	if updated["r1"] != 37.0 || updated["r2"] != 19.0 || appended != nil {
+		t.Errorf("updated %v, appended %v", updated, appended)
+	}
+
+	type record struct {
+		ID  string `seatable:"_id"`
+		Age int    `seatable:"age"`
+	}
+	updated = nil
+	ret, err = b.UpsertRows("table1", "_id", []interface{}{&record{ID: "r2", Age: 20}})
+	if err != nil || ret.Updated != 1 || updated["r2"] != 20.0 {
+		t.Errorf("upsert by _id = %+v, %v, updated %v", ret, err, updated)
+	}
+
+	appended = nil
+	_, err = b.UpsertRows("table1", "Name", []interface{}{
+		map[string]interface{}{"Name": "Dan", "age": 1},
+		person{Name: "Dan", Age: 2},
+	})
+	if err == nil || appended != nil {
+		t.Errorf("expected an error for duplicate keys, got %v, appended %v", err, appended)
+	}
+
+	if _, err := b.UpsertRows("table1", "Missing", nil); err == nil {
+		t.Error("expected an error for an unknown key column")
+	}
+}
+
+func TestBatchAppendRowsWithOptions(t *testing.T) {
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+
+	si := newStandIn(t)
+	si.handle("/batch-append-rows/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		var req struct {
+			Rows []map[string]interface{} `json:"rows"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		ids := []interface{}{}
+		for _, row := range req.Rows {
+			if row["Name"] == "bad" {
+				w.WriteHeader(http.StatusBadRequest)
+				writeJSON(w, map[string]interface{}{"error_msg": "invalid row"})
+				return
+			}
+			ids = append(ids, map[string]interface{}{"_id": fmt.Sprintf("id-%v", row["Name"])})
+		}
+		writeJSON(w, map[string]interface{}{"inserted_row_count": len(ids), "row_ids": ids})
+	})
+	b := si.base(t)
+
+	rows := make([]interface{}, 95)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"Name": i}
+	}
+	ret, err := b.BatchAppendRowsWithOptions("table1", rows, &BatchOptions{ChunkSize: 10, Concurrency: 3})
+	if err != nil {
+		t.Fatalf("batch append failed: %v", err)
+	}
+	if len(ret.Chunks) != 10 || maxActive > 3 {
+		t.Errorf("got %d chunks with %d in parallel", len(ret.Chunks), maxActive)
+	}
+	for i, id := range ret.RowIDs {
+		if id != fmt.Sprintf("id-%d", i) {
+			t.Fatalf("row %d has id %s", i, id)
+		}
+	}
+	if n := ret.Response()["inserted_row_count"]; n != 95.0 {
+		t.Errorf("inserted_row_count = %v", n)
+	}
+
+	rows[42] = map[string]interface{}{"Name": "bad"}
+	ret, err = b.BatchAppendRowsWithOptions("table1", rows, &BatchOptions{ChunkSize: 10, Concurrency: 3})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	failed := ret.Failed()
+	if len(failed) != 1 || failed[0].Start != 40 || failed[0].End != 50 || len(ret.Succeeded()) != 9 {
+		t.Errorf("unexpected chunks, failed %+v", failed)
+	}
+	if ret.RowIDs[41] != "" || ret.RowIDs[50] != "id-50" {
+		t.Errorf("unexpected row ids around the failed chunk")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected the api error, got %v", err)
+	}
+}
+
+func TestBatchDeleteRowsChunks(t *testing.T) {
+	var sizes []int
+	si := newStandIn(t)
+	si.handle("/batch-delete-rows/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			RowIDs []string `json:"row_ids"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		sizes = append(sizes, len(req.RowIDs))
+		writeJSON(w, map[string]interface{}{"deleted_rows": len(req.RowIDs)})
+	})
+	b := si.base(t)
+
+	ids := make([]interface{}, 1500)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("row%d", i)
+	}
+	ret, err := b.BatchDeleteRows("table1", ids)
+	if err != nil {
+		t.Fatalf("batch delete failed: %v", err)
+	}
+	if len(sizes) != 2 || ret["deleted_rows"] != 1500.0 {
+		t.Errorf("got chunks %v, response %v", sizes, ret)
+	}
+}
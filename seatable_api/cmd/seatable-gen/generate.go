@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	seatable "github.com/seatable/seatable-api-go/seatable_api"
+)
+
+type generator struct {
+	buf     bytes.Buffer
+	imports map[string]bool
+	names   map[string]bool
+}
+
+// Generate returns the formatted Go source for the tables in md.
+func Generate(md *seatable.Metadata, pkg string) ([]byte, error) {
+	g := &generator{imports: make(map[string]bool), names: make(map[string]bool)}
+
+	var body bytes.Buffer
+	for _, table := range md.Tables {
+		g.buf.Reset()
+		g.table(table)
+		body.Write(g.buf.Bytes())
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by seatable-gen. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	if len(g.imports) > 0 {
+		var std, other []string
+		for p := range g.imports {
+			if strings.Contains(p, ".") {
+				other = append(other, p)
+			} else {
+				std = append(std, p)
+			}
+		}
+		sort.Strings(std)
+		sort.Strings(other)
+
+		src.WriteString("import (\n")
+		for _, p := range std {
+			fmt.Fprintf(&src, "\t%q\n", p)
+		}
+		if len(std) > 0 && len(other) > 0 {
+			src.WriteString("\n")
+		}
+		for _, p := range other {
+			fmt.Fprintf(&src, "\t%q\n", p)
+		}
+		src.WriteString(")\n\n")
+	}
+	src.Write(body.Bytes())
+
+	ret, err := format.Source(src.Bytes())
+	if err != nil {
+		err := fmt.Errorf("failed to format generated code: %v", err)
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (g *generator) table(table *seatable.Table) {
+	typeName := g.unique(identifier(table.Name))
+
+	fmt.Fprintf(&g.buf, "const %s = %q\n\n", g.unique("Table"+typeName), table.Name)
+
+	// Column name constants.
+	fieldNames := map[string]bool{"ID": true}
+	fields := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		fields[i] = uniqueIn(fieldNames, identifier(col.Name))
+	}
+
+	if len(table.Columns) > 0 {
+		g.buf.WriteString("const (\n")
+		for i, col := range table.Columns {
+			fmt.Fprintf(&g.buf, "\t%s = %q\n", g.unique(typeName+fields[i]+"Column"), col.Name)
+		}
+		g.buf.WriteString(")\n\n")
+	}
+
+	// Select option enums.
+	enums := make(map[string]string)
+	for i, col := range table.Columns {
+		if col.Type != seatable.SINGLE_SELECT && col.Type != seatable.MULTIPLE_SELECT {
+			continue
+		}
+		enum := g.unique(typeName + fields[i])
+		enums[col.Key] = enum
+		fmt.Fprintf(&g.buf, "type %s string\n\n", enum)
+
+		if col.Data == nil || len(col.Data.Options) == 0 {
+			continue
+		}
+		g.buf.WriteString("const (\n")
+		for _, o := range col.Data.Options {
+			name := g.unique(enum + identifier(o.Name))
+			fmt.Fprintf(&g.buf, "\t%s %s = %q\n", name, enum, o.Name)
+		}
+		g.buf.WriteString(")\n\n")
+	}
+
+	fmt.Fprintf(&g.buf, "// %s is a row of the table %q.\n", typeName, table.Name)
+	fmt.Fprintf(&g.buf, "type %s struct {\n", typeName)
+	fmt.Fprintf(&g.buf, "\tID string `seatable:\"_id\"`\n")
+	for i, col := range table.Columns {
+		goType, opts := g.fieldType(col, enums[col.Key])
+		if goType == "" {
+			continue
+		}
+		tag := col.Name
+		if opts != "" {
+			tag += "," + opts
+		}
+		fmt.Fprintf(&g.buf, "\t%s %s `seatable:%s`\n", fields[i], goType, strconv.Quote(tag))
+	}
+	g.buf.WriteString("}\n\n")
+}
+
+// fieldType returns the Go type and tag options for a column, or an
+// empty type for columns that hold no data.
+func (g *generator) fieldType(col *seatable.Column, enum string) (string, string) {
+	switch col.Type {
+	case seatable.TEXT, seatable.LONG_TEXT, seatable.EMAIL, seatable.URL:
+		return "string", ""
+	case seatable.NUMBER, seatable.RATING, seatable.DURATION:
+		return "*float64", ""
+	case seatable.CHECKBOX:
+		return "bool", ""
+	case seatable.DATE:
+		g.imports["time"] = true
+		if col.Data != nil && !strings.Contains(col.Data.Format, "HH:mm") {
+			return "*time.Time", "date"
+		}
+		return "*time.Time", ""
+	case seatable.SINGLE_SELECT:
+		return enum, ""
+	case seatable.MULTIPLE_SELECT:
+		return "[]" + enum, ""
+	case seatable.COLLABORATOR, seatable.IMAGE:
+		return "[]string", ""
+	case seatable.FILE:
+		g.imports["github.com/seatable/seatable-api-go/seatable_api"] = true
+		return "[]*seatable_api.FileValue", ""
+	case seatable.GEOLOCATION:
+		g.imports["github.com/seatable/seatable-api-go/seatable_api"] = true
+		return "*seatable_api.GeolocationValue", ""
+	case seatable.LINK:
+		g.imports["github.com/seatable/seatable-api-go/seatable_api"] = true
+		return "[]*seatable_api.LinkValue", "readonly"
+	case seatable.CREATOR, seatable.LAST_MODIFIER, seatable.AUTO_NUMBER:
+		return "string", "readonly"
+	case seatable.CTIME, seatable.MTIME:
+		g.imports["time"] = true
+		return "*time.Time", "readonly"
+	case seatable.FORMULA, seatable.LINK_FORMULA:
+		resultType := ""
+		if col.Data != nil {
+			resultType = col.Data.ResultType
+		}
+		switch resultType {
+		case "number":
+			return "*float64", "readonly"
+		case "string":
+			return "string", "readonly"
+		case "bool":
+			return "bool", "readonly"
+		case "date":
+			g.imports["time"] = true
+			return "*time.Time", "readonly"
+		}
+		return "interface{}", "readonly"
+	case seatable.BUTTON:
+		return "", ""
+	}
+	return "interface{}", ""
+}
+
+func (g *generator) unique(name string) string {
+	return uniqueIn(g.names, name)
+}
+
+func uniqueIn(names map[string]bool, name string) string {
+	ret := name
+	for i := 2; names[ret]; i++ {
+		ret = name + strconv.Itoa(i)
+	}
+	names[ret] = true
+	return ret
+}
+
+// identifier turns a table, column or option name into an exported Go
+// identifier, e.g. "due date" into "DueDate".
+func identifier(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	ret := b.String()
+	if ret == "" {
+		return "X"
+	}
+	if first := []rune(ret)[0]; !unicode.IsUpper(first) {
+		ret = "X" + ret
+	}
+	return ret
+}
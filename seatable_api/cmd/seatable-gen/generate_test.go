@@ -0,0 +1,141 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/metadata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, err := parseSnapshot(b)
+	if err != nil {
+		t.Fatalf("failed to parse snapshot: %v", err)
+	}
+
+	src, err := Generate(md, "schema")
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "schema.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+
+	for _, want := range []string{
+		`const TableTable1 = "table1"`,
+		`Table1StatusColumn      = "Status"`,
+		`Table1StatusDone Table1Status = "Done"`,
+		"Birthday    *time.Time                     `seatable:\"Birthday,date\"`",
+		"Tags        []Table1Tags                   `seatable:\"Tags\"`",
+		"Projects    []*seatable_api.LinkValue      `seatable:\"Projects,readonly\"`",
+		"Title string `seatable:\"Title\"`",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code lacks %q", want)
+		}
+	}
+}
+
+// roundTripMain decodes a row as ListRows returns it, with links as row
+// ids, into the generated struct.
+const roundTripMain = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/seatable/seatable-api-go/seatable_api"
+)
+
+const row = ` + "`" + `{
+	"_id": "Qtf7xPmoRaiFyQPO1aENTjb",
+	"Name": "name1",
+	"age": 20,
+	"Birthday": "2021-03-05",
+	"Status": "Open",
+	"Tags": ["red", "blue"],
+	"Done": true,
+	"Projects": ["rowA", "rowB"],
+	"Place": {"lng": 13.4, "lat": 52.5}
+}` + "`" + `
+
+func main() {
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(row), &values); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	var r Table1
+	if err := seatable_api.Unmarshal(values, &r); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println(r.ID, r.Name, *r.Age, r.Birthday.Format("2006-01-02"), r.Status, r.Tags, r.Done,
+		len(r.Projects), r.Projects[0].RowID, r.Projects[1].RowID, r.Place.Lat)
+}
+`
+
+func TestGenerateUnmarshal(t *testing.T) {
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	b, err := ioutil.ReadFile("testdata/metadata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, err := parseSnapshot(b)
+	if err != nil {
+		t.Fatalf("failed to parse snapshot: %v", err)
+	}
+	src, err := Generate(md, "main")
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	// The program has to live inside the module to import seatable_api.
+	dir, err := ioutil.TempDir(".", "_roundtrip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "schema.go"), src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(roundTripMain), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command(goCmd, "run", "./"+filepath.Base(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("generated code failed: %v\n%s\n%s", err, out, src)
+	}
+	want := "Qtf7xPmoRaiFyQPO1aENTjb name1 20 2021-03-05 Open [red blue] true 2 rowA rowB 52.5\n"
+	if string(out) != want {
+		t.Errorf("decoded %q, want %q", out, want)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	for name, want := range map[string]string{
+		"due date":   "DueDate",
+		"Table1":     "Table1",
+		"2nd column": "X2ndColumn",
+		"e-mail":     "EMail",
+		"größe":      "Größe",
+		"":           "X",
+	} {
+		if got := identifier(name); got != want {
+			t.Errorf("identifier(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
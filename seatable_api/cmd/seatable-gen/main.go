@@ -0,0 +1,110 @@
+// Command seatable-gen generates Go types from the schema of a SeaTable
+// base: a struct with seatable tags per table, constants for table and
+// column names and an enum type per select column.
+//
+// Usage:
+//
+//	seatable-gen -server https://cloud.seatable.io -token <api token> -o schema.go
+//	seatable-gen -snapshot metadata.json -package schema -o schema.go
+//
+// The metadata of a live base can be saved with -save and checked into
+// the repository, so that the code can be regenerated offline.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	seatable "github.com/seatable/seatable-api-go/seatable_api"
+)
+
+func main() {
+	server := flag.String("server", os.Getenv("SEATABLE_SERVER_URL"), "SeaTable server url")
+	token := flag.String("token", os.Getenv("SEATABLE_API_TOKEN"), "API token of the base")
+	snapshot := flag.String("snapshot", "", "read metadata from this JSON file instead of the server")
+	save := flag.String("save", "", "save the metadata read from the server to this file")
+	pkg := flag.String("package", "schema", "package name of the generated code")
+	out := flag.String("o", "", "output file, defaults to stdout")
+	flag.Parse()
+
+	if err := run(*server, *token, *snapshot, *save, *pkg, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "seatable-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(server, token, snapshot, save, pkg, out string) error {
+	md, err := loadMetadata(server, token, snapshot)
+	if err != nil {
+		return err
+	}
+
+	if save != "" {
+		b, err := json.MarshalIndent(map[string]interface{}{"metadata": md}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(save, b, 0644); err != nil {
+			err := fmt.Errorf("failed to save metadata: %v", err)
+			return err
+		}
+	}
+
+	src, err := Generate(md, pkg)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(out, src, 0644)
+}
+
+func loadMetadata(server, token, snapshot string) (*seatable.Metadata, error) {
+	if snapshot != "" {
+		b, err := ioutil.ReadFile(snapshot)
+		if err != nil {
+			err := fmt.Errorf("failed to read snapshot: %v", err)
+			return nil, err
+		}
+		return parseSnapshot(b)
+	}
+
+	if server == "" || token == "" {
+		err := fmt.Errorf("either -snapshot or -server and -token are required")
+		return nil, err
+	}
+	base := seatable.Init(token, server)
+	if err := base.Auth(false); err != nil {
+		err := fmt.Errorf("failed to auth: %v", err)
+		return nil, err
+	}
+	return base.GetMetadata()
+}
+
+// parseSnapshot accepts the response of the metadata endpoint as well as
+// the bare metadata object.
+func parseSnapshot(b []byte) (*seatable.Metadata, error) {
+	var wrapped struct {
+		Metadata *seatable.Metadata `json:"metadata"`
+	}
+	if err := json.Unmarshal(b, &wrapped); err != nil {
+		err := fmt.Errorf("failed to parse snapshot: %v", err)
+		return nil, err
+	}
+	if wrapped.Metadata != nil {
+		return wrapped.Metadata, nil
+	}
+
+	md := new(seatable.Metadata)
+	if err := json.Unmarshal(b, md); err != nil {
+		err := fmt.Errorf("failed to parse snapshot: %v", err)
+		return nil, err
+	}
+	return md, nil
+}
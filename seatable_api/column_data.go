@@ -0,0 +1,75 @@
+package seatable_api
+
+// The types below are sent as column data when inserting or changing a
+// column of the corresponding type.
+
+// TypedColumnData is implemented by the column data types below.
+type TypedColumnData interface {
+	// validFor reports whether the data applies to columns of type t.
+	validFor(t ColumnTypes) bool
+}
+
+type NumberColumnData struct {
+	Format NumberFormat `json:"format"`
+	// Decimal is the decimal separator, "dot" or "comma".
+	Decimal string `json:"decimal,omitempty"`
+	// Thousands is the thousands separator, "no", "comma", "dot" or "space".
+	Thousands string `json:"thousands,omitempty"`
+	// Precision is the number of decimals shown if EnablePrecision is set.
+	Precision       int    `json:"precision"`
+	EnablePrecision bool   `json:"enable_precision,omitempty"`
+	CurrencySymbol  string `json:"currency_symbol,omitempty"`
+}
+
+type DateColumnData struct {
+	Format DateFormat `json:"format"`
+}
+
+type DurationColumnData struct {
+	Format         string         `json:"format"`
+	DurationFormat DurationFormat `json:"duration_format"`
+}
+
+type SelectColumnData struct {
+	Options []*SelectOption `json:"options"`
+}
+
+// LinkColumnData links the table to OtherTable. Both are table names.
+type LinkColumnData struct {
+	Table      string `json:"table"`
+	OtherTable string `json:"other_table"`
+}
+
+type FormulaColumnData struct {
+	Formula string `json:"formula"`
+}
+
+type RatingColumnData struct {
+	RatingMaxNumber int    `json:"rating_max_number"`
+	RatingStyle     string `json:"rating_style,omitempty"`
+}
+
+type AutoNumberColumnData struct {
+	Format string `json:"format"`
+}
+
+type GeolocationColumnData struct {
+	GeoFormat string `json:"geo_format"`
+}
+
+func NewDurationColumnData(format DurationFormat) *DurationColumnData {
+	return &DurationColumnData{Format: "duration", DurationFormat: format}
+}
+
+func (*NumberColumnData) validFor(t ColumnTypes) bool      { return t == NUMBER }
+func (*DateColumnData) validFor(t ColumnTypes) bool        { return t == DATE }
+func (*DurationColumnData) validFor(t ColumnTypes) bool    { return t == DURATION }
+func (*LinkColumnData) validFor(t ColumnTypes) bool        { return t == LINK }
+func (*FormulaColumnData) validFor(t ColumnTypes) bool     { return t == FORMULA }
+func (*RatingColumnData) validFor(t ColumnTypes) bool      { return t == RATING }
+func (*AutoNumberColumnData) validFor(t ColumnTypes) bool  { return t == AUTO_NUMBER }
+func (*GeolocationColumnData) validFor(t ColumnTypes) bool { return t == GEOLOCATION }
+
+func (*SelectColumnData) validFor(t ColumnTypes) bool {
+	return t == SINGLE_SELECT || t == MULTIPLE_SELECT
+}
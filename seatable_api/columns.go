@@ -0,0 +1,231 @@
+package seatable_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strconv"
+)
+
+// getColumn looks up a column by key or name in the table metadata.
+func (s *Base) getColumn(ctx context.Context, tableName, column string) (*Column, error) {
+	table, err := s.GetTableCtx(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	col := table.ColumnByKey(column)
+	if col == nil {
+		col = table.ColumnByName(column)
+	}
+	if col == nil {
+		err := fmt.Errorf("column %s in table %s: %w", column, tableName, ErrNotFound)
+		return nil, err
+	}
+	return col, nil
+}
+
+// UpdateColumnData replaces the type specific settings of a column with
+// columnData, the column data type matching the column type, e.g.
+// NumberColumnData for a NUMBER column. Other data is rejected before it
+// is sent.
+func (s *Base) UpdateColumnData(tableName, columnKey string, columnData TypedColumnData) (map[string]interface{}, error) {
+	return s.UpdateColumnDataCtx(context.Background(), tableName, columnKey, columnData)
+}
+
+func (s *Base) UpdateColumnDataCtx(ctx context.Context, tableName, columnKey string, columnData TypedColumnData) (map[string]interface{}, error) {
+	if columnData == nil {
+		err := fmt.Errorf("column data can not be empty")
+		return nil, err
+	}
+	col, err := s.getColumn(ctx, tableName, columnKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.setColumnData(ctx, tableName, col, columnData)
+}
+
+// setColumnData sends columnData for a column already looked up, after
+// checking that it matches the column type.
+func (s *Base) setColumnData(ctx context.Context, tableName string, col *Column, columnData TypedColumnData) (map[string]interface{}, error) {
+	if !columnData.validFor(col.Type) {
+		err := fmt.Errorf("column data %T invalid for column %s of type %s", columnData, col.Name, col.Type)
+		return nil, err
+	}
+
+	data := make(map[string]interface{})
+	data["op_type"] = SET_COLUMN_DATA
+	data["table_name"] = tableName
+	data["column_key"] = col.Key
+	data["column_data"] = columnData
+
+	return s.putColumn(ctx, data)
+}
+
+// SetColumnFormula changes the formula of a formula column.
+func (s *Base) SetColumnFormula(tableName, columnKey, formula string) (map[string]interface{}, error) {
+	return s.SetColumnFormulaCtx(context.Background(), tableName, columnKey, formula)
+}
+
+func (s *Base) SetColumnFormulaCtx(ctx context.Context, tableName, columnKey, formula string) (map[string]interface{}, error) {
+	col, err := s.getColumn(ctx, tableName, columnKey)
+	if err != nil {
+		return nil, err
+	}
+	if col.Type != FORMULA {
+		err := fmt.Errorf("column %s of type %s is not a formula column", col.Name, col.Type)
+		return nil, err
+	}
+
+	return s.setColumnData(ctx, tableName, col, &FormulaColumnData{Formula: formula})
+}
+
+// AddColumnOptions adds options to a single-select or multiple-select
+// column. The options get new ids; their names must not exist yet.
+func (s *Base) AddColumnOptions(tableName, columnKey string, options []*SelectOption) (map[string]interface{}, error) {
+	return s.AddColumnOptionsCtx(context.Background(), tableName, columnKey, options)
+}
+
+func (s *Base) AddColumnOptionsCtx(ctx context.Context, tableName, columnKey string, options []*SelectOption) (map[string]interface{}, error) {
+	col, err := s.selectColumn(ctx, tableName, columnKey)
+	if err != nil {
+		return nil, err
+	}
+
+	list := columnOptions(col)
+	ids := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, o := range list {
+		ids[o.ID] = true
+		names[o.Name] = true
+	}
+
+	for _, o := range options {
+		if o.Name == "" || names[o.Name] {
+			err := fmt.Errorf("option %q invalid or already in column %s", o.Name, col.Name)
+			return nil, err
+		}
+		names[o.Name] = true
+
+		id := newOptionID(ids)
+		list = append(list, &SelectOption{ID: id, Name: o.Name, Color: o.Color, TextColor: o.TextColor})
+	}
+
+	return s.setColumnData(ctx, tableName, col, &SelectColumnData{Options: list})
+}
+
+// UpdateColumnOptions changes the name and colors of options of a select
+// column. Options are matched by ID, or by Name if ID is empty; empty
+// fields are left unchanged.
+func (s *Base) UpdateColumnOptions(tableName, columnKey string, options []*SelectOption) (map[string]interface{}, error) {
+	return s.UpdateColumnOptionsCtx(context.Background(), tableName, columnKey, options)
+}
+
+func (s *Base) UpdateColumnOptionsCtx(ctx context.Context, tableName, columnKey string, options []*SelectOption) (map[string]interface{}, error) {
+	col, err := s.selectColumn(ctx, tableName, columnKey)
+	if err != nil {
+		return nil, err
+	}
+
+	list := columnOptions(col)
+	for _, o := range options {
+		var found *SelectOption
+		for _, v := range list {
+			if (o.ID != "" && v.ID == o.ID) || (o.ID == "" && v.Name == o.Name) {
+				found = v
+				break
+			}
+		}
+		if found == nil {
+			err := fmt.Errorf("option %s in column %s: %w", o.Name, col.Name, ErrNotFound)
+			return nil, err
+		}
+
+		if o.Name != "" {
+			found.Name = o.Name
+		}
+		if o.Color != "" {
+			found.Color = o.Color
+		}
+		if o.TextColor != "" {
+			found.TextColor = o.TextColor
+		}
+	}
+
+	return s.setColumnData(ctx, tableName, col, &SelectColumnData{Options: list})
+}
+
+func (s *Base) selectColumn(ctx context.Context, tableName, columnKey string) (*Column, error) {
+	col, err := s.getColumn(ctx, tableName, columnKey)
+	if err != nil {
+		return nil, err
+	}
+	if col.Type != SINGLE_SELECT && col.Type != MULTIPLE_SELECT {
+		err := fmt.Errorf("column %s of type %s has no options", col.Name, col.Type)
+		return nil, err
+	}
+	return col, nil
+}
+
+// columnOptions copies the options of a column so they can be changed
+// without touching the metadata.
+func columnOptions(col *Column) []*SelectOption {
+	var list []*SelectOption
+	if col.Data != nil {
+		for _, o := range col.Data.Options {
+			v := *o
+			list = append(list, &v)
+		}
+	}
+	return list
+}
+
+// newOptionID returns a random six digit option id not in ids, like the
+// ids SeaTable generates.
+func newOptionID(ids map[string]bool) string {
+	for {
+		id := strconv.Itoa(100000 + rand.Intn(900000))
+		if !ids[id] {
+			ids[id] = true
+			return id
+		}
+	}
+}
+
+func (s *Base) putColumn(ctx context.Context, data map[string]interface{}) (map[string]interface{}, error) {
+	url := s.dtableURL("/columns/")
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		err := fmt.Errorf("failed to encode put data: %v", err)
+		return nil, err
+	}
+
+	status, body, err := s.httpPut(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to post column to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("PUT", url, status, body)
+		return nil, err
+	}
+
+	rsp, err := parseResponse(body)
+	if err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+
+	ret, ok := rsp.(map[string]interface{})
+	if !ok {
+		err := fmt.Errorf("failed to assert response")
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,146 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestColumnOperations(t *testing.T) {
+	var ops []map[string]interface{}
+	si := newStandIn(t)
+	si.handle("/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMetadata))
+	})
+	si.handle("/columns/", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		ops = append(ops, req)
+		writeJSON(w, map[string]interface{}{"success": true})
+	})
+	b := si.base(t)
+
+	if _, err := b.MoveColumn("table1", "a1b2", "0000"); err != nil {
+		t.Fatalf("failed to move column: %v", err)
+	}
+	if op := ops[0]; op["op_type"] != "move_column" || op["target_column_key"] != "0000" {
+		t.Errorf("unexpected move %v", op)
+	}
+
+	if _, err := b.SetColumnFormula("table1", "u1v2", "{age} * 3"); err != nil {
+		t.Fatalf("failed to set formula: %v", err)
+	}
+	data, _ := ops[1]["column_data"].(map[string]interface{})
+	if ops[1]["op_type"] != "set_column_data" || ops[1]["column_key"] != "u1v2" || data["formula"] != "{age} * 3" {
+		t.Errorf("unexpected formula update %v", ops[1])
+	}
+	if _, err := b.SetColumnFormula("table1", "a1b2", "1"); err == nil {
+		t.Error("expected an error for a number column")
+	}
+
+	if _, err := b.UpdateColumnData("table1", "age", &NumberColumnData{Format: NUMBER_FORMAT_EURO}); err != nil {
+		t.Fatalf("failed to update column data: %v", err)
+	}
+	if ops[2]["column_key"] != "a1b2" {
+		t.Errorf("unexpected column data update %v", ops[2])
+	}
+	if _, err := b.UpdateColumnData("table1", "Birthday", &NumberColumnData{}); err == nil || len(ops) != 3 {
+		t.Error("expected an error for number data on a date column")
+	}
+	if _, err := b.UpdateColumnData("table1", "Missing", &NumberColumnData{}); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+
+	if _, err := b.AddColumnOptions("table1", "Status", []*SelectOption{{Name: "Blocked", Color: "#FF0000"}}); err != nil {
+		t.Fatalf("failed to add options: %v", err)
+	}
+	var options SelectColumnData
+	convertValue(ops[3]["column_data"], &options)
+	if len(options.Options) != 3 || options.Options[2].Name != "Blocked" || len(options.Options[2].ID) != 6 {
+		t.Errorf("unexpected options %+v", options.Options)
+	}
+	if _, err := b.AddColumnOptions("table1", "e5f6", []*SelectOption{{Name: "Open"}}); err == nil {
+		t.Error("expected an error for a duplicate option")
+	}
+
+	_, err := b.UpdateColumnOptions("table1", "e5f6", []*SelectOption{{ID: "222222", Name: "Closed"}, {Name: "Open", Color: "#00FF00"}})
+	if err != nil {
+		t.Fatalf("failed to update options: %v", err)
+	}
+	convertValue(ops[4]["column_data"], &options)
+	if options.Options[1].Name != "Closed" || options.Options[0].Color != "#00FF00" || options.Options[0].Name != "Open" {
+		t.Errorf("unexpected options %+v %+v", options.Options[0], options.Options[1])
+	}
+	if _, err := b.UpdateColumnOptions("table1", "e5f6", []*SelectOption{{Name: "Missing"}}); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if _, err := b.AddColumnOptions("table1", "Name", []*SelectOption{{Name: "x"}}); err == nil {
+		t.Error("expected an error for a text column")
+	}
+}
+
+func TestColumnTypesIsValid(t *testing.T) {
+	for _, ct := range []ColumnTypes{TEXT, NUMBER, LINK_FORMULA, DIGITAL_SIGN} {
+		if !ct.IsValid() {
+			t.Errorf("%s not valid", ct)
+		}
+	}
+	for _, ct := range []ColumnTypes{"", "Text", "rate", "long_text"} {
+		if ct.IsValid() {
+			t.Errorf("%q valid", ct)
+		}
+	}
+}
+
+func TestInsertColumnWithData(t *testing.T) {
+	var ops []map[string]interface{}
+	si := newStandIn(t)
+	si.handle("/columns/", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		ops = append(ops, req)
+		writeJSON(w, map[string]interface{}{"success": true})
+	})
+	b := si.base(t)
+
+	if _, err := b.InsertColumn("table1", "Notes", LONG_TEXT, ""); err != nil {
+		t.Fatalf("failed to insert column: %v", err)
+	}
+	if _, ok := ops[0]["column_data"]; ok || ops[0]["column_type"] != "long-text" {
+		t.Errorf("unexpected insert %v", ops[0])
+	}
+
+	price := &NumberColumnData{Format: NUMBER_FORMAT_CUSTOM_CURRENCY, CurrencySymbol: "CHF", EnablePrecision: true}
+	if _, err := b.InsertColumnWithData("table1", "Price", NUMBER, "a1b2", price); err != nil {
+		t.Fatalf("failed to insert column: %v", err)
+	}
+	want := map[string]interface{}{
+		"table_name":  "table1",
+		"column_name": "Price",
+		"column_type": "number",
+		"column_key":  "a1b2",
+		"column_data": map[string]interface{}{
+			"format":           "custom_currency",
+			"currency_symbol":  "CHF",
+			"enable_precision": true,
+			"precision":        float64(0),
+		},
+	}
+	if !reflect.DeepEqual(ops[1], want) {
+		t.Errorf("insert = %v, want %v", ops[1], want)
+	}
+
+	if _, err := b.InsertColumnWithData("table1", "Due", DATE, "", price); err == nil {
+		t.Error("expected an error for number data on a date column")
+	}
+	if _, err := b.InsertColumn("table1", "Bad", "bogus", ""); err == nil {
+		t.Error("expected an error for an invalid column type")
+	}
+	if _, err := b.ModifyColumnType("table1", "a1b2", "bogus"); err == nil {
+		t.Error("expected an error for an invalid column type")
+	}
+	if len(ops) != 2 {
+		t.Errorf("invalid columns were sent: %v", ops[2:])
+	}
+}
@@ -0,0 +1,65 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentAppendRowAndAuth(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		var data map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"_id": "row", "row": data["row"]})
+	})
+	b := si.base(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_, err := b.AppendRow(tableName, map[string]interface{}{"Name": fmt.Sprintf("name%d", i)})
+			errs <- err
+		}(i)
+		go func() {
+			defer wg.Done()
+			errs <- b.Auth(false)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent request failed: %v", err)
+		}
+	}
+}
+
+func TestCloneSharesClient(t *testing.T) {
+	si := newStandIn(t)
+	limit := RateLimit{Requests: 10, Per: time.Second}
+	b := si.base(t, WithServerRateLimit(limit))
+
+	c, err := b.Clone()
+	if err != nil {
+		t.Fatalf("failed to clone base: %v", err)
+	}
+	if c.client() != b.client() || c.limits != b.limits {
+		t.Errorf("clone does not share http client and rate limiter")
+	}
+
+	c.Headers["Authorization"] = "Token other"
+	if b.Headers["Authorization"] == "Token other" {
+		t.Errorf("clone shares credentials with original")
+	}
+}
@@ -0,0 +1,613 @@
+package seatable_api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Conditions select rows for Filter and QuerySet.Filter, e.g.
+//
+//	Name = 'x' and age >= 10 or Status in ('A', 'B')
+//
+// A condition compares a column with a value using =, !=, <>, <, <=, >,
+// >=, like and not like (with % and _ wildcards, ignoring case), in and
+// not in, or tests it with is null and is not null. Conditions are
+// combined with and, or and not, and grouped with parentheses; and binds
+// tighter than or. Column names containing spaces or keywords are quoted
+// with backticks. Values are quoted strings, numbers, true, false or null.
+//
+// Cells are compared by column type: numbers, durations and ratings
+// numerically, dates and times chronologically (a date without time
+// matches the whole day), checkboxes as booleans and everything else as
+// text. Select options are compared by name. A condition on a
+// multiple-value cell, such as a multiple-select or collaborator cell,
+// holds when it holds for any of its values.
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenColumn
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type condToken struct {
+	kind  tokenKind
+	text  string
+	pos   int
+	value interface{}
+}
+
+func (t condToken) is(keyword string) bool {
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+func tokenize(s string) ([]condToken, error) {
+	var tokens []condToken
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, condToken{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, condToken{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, condToken{kind: tokenComma, text: ",", pos: i})
+			i++
+		case r == '\'' || r == '"' || r == '`':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				} else if runes[j] == r {
+					break
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				err := fmt.Errorf("unterminated %c at position %d", r, i)
+				return nil, err
+			}
+			kind := tokenString
+			if r == '`' {
+				kind = tokenColumn
+			}
+			tokens = append(tokens, condToken{kind: kind, text: b.String(), pos: i, value: b.String()})
+			i = j + 1
+		case strings.ContainsRune("=!<>", r):
+			j := i + 1
+			if j < len(runes) && (runes[j] == '=' || (r == '<' && runes[j] == '>')) {
+				j++
+			}
+			op := string(runes[i:j])
+			switch op {
+			case "!":
+				err := fmt.Errorf("unexpected ! at position %d", i)
+				return nil, err
+			case "==":
+				op = "="
+			case "<>":
+				op = "!="
+			}
+			tokens = append(tokens, condToken{kind: tokenOperator, text: op, pos: i})
+			i = j
+		case unicode.IsDigit(r) || (r == '-' || r == '.') && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			text := string(runes[i:j])
+			f, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				err := fmt.Errorf("invalid number %s at position %d", text, i)
+				return nil, err
+			}
+			tokens = append(tokens, condToken{kind: tokenNumber, text: text, pos: i, value: f})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, condToken{kind: tokenIdent, text: string(runes[i:j]), pos: i})
+			i = j
+		default:
+			err := fmt.Errorf("unexpected %c at position %d", r, i)
+			return nil, err
+		}
+	}
+	tokens = append(tokens, condToken{kind: tokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+// condition is a parsed condition string.
+type condition interface {
+	match(r *Row) (bool, error)
+}
+
+type andCondition struct {
+	left, right condition
+}
+
+func (c *andCondition) match(r *Row) (bool, error) {
+	ok, err := c.left.match(r)
+	if err != nil || !ok {
+		return false, err
+	}
+	return c.right.match(r)
+}
+
+type orCondition struct {
+	left, right condition
+}
+
+func (c *orCondition) match(r *Row) (bool, error) {
+	ok, err := c.left.match(r)
+	if err != nil || ok {
+		return ok, err
+	}
+	return c.right.match(r)
+}
+
+type notCondition struct {
+	cond condition
+}
+
+func (c *notCondition) match(r *Row) (bool, error) {
+	ok, err := c.cond.match(r)
+	return !ok, err
+}
+
+type compareCondition struct {
+	column string
+	op     string
+	values []interface{}
+	like   *regexp.Regexp
+}
+
+type conditionParser struct {
+	tokens []condToken
+	pos    int
+	table  *Table
+}
+
+// parseConditions parses a condition string and checks its columns
+// against table, which may be nil.
+func parseConditions(s string, table *Table) (condition, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		err := fmt.Errorf("invalid conditions: %v", err)
+		return nil, err
+	}
+
+	p := &conditionParser{tokens: tokens, table: table}
+	cond, err := p.parseOr()
+	if err != nil {
+		err := fmt.Errorf("invalid conditions: %v", err)
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		err := fmt.Errorf("invalid conditions: unexpected %s at position %d", t.text, t.pos)
+		return nil, err
+	}
+	return cond, nil
+}
+
+func (p *conditionParser) peek() condToken {
+	return p.tokens[p.pos]
+}
+
+func (p *conditionParser) next() condToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *conditionParser) unexpected(t condToken, want string) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf("expect %s at end of conditions", want)
+	}
+	return fmt.Errorf("expect %s at position %d, got %s", want, t.pos, t.text)
+}
+
+func (p *conditionParser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orCondition{left, right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (condition, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andCondition{left, right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (condition, error) {
+	t := p.peek()
+	if t.is("not") {
+		p.next()
+		cond, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notCondition{cond}, nil
+	}
+	if t.kind == tokenLParen {
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, p.unexpected(t, ")")
+		}
+		return cond, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *conditionParser) parseComparison() (condition, error) {
+	t := p.next()
+	if t.kind != tokenIdent && t.kind != tokenColumn {
+		return nil, p.unexpected(t, "column name")
+	}
+	column := t.text
+	if err := p.checkColumn(column); err != nil {
+		return nil, err
+	}
+
+	cond := &compareCondition{column: column}
+	t = p.next()
+	switch {
+	case t.kind == tokenOperator:
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		cond.op = t.text
+		cond.values = []interface{}{v}
+		if v == nil && t.text == "=" {
+			cond.op = "is null"
+		} else if v == nil && t.text == "!=" {
+			cond.op = "is not null"
+		}
+	case t.is("is"):
+		cond.op = "is null"
+		if p.peek().is("not") {
+			p.next()
+			cond.op = "is not null"
+		}
+		if t := p.next(); !t.is("null") {
+			return nil, p.unexpected(t, "null")
+		}
+	case t.is("not") || t.is("in") || t.is("like"):
+		cond.op = strings.ToLower(t.text)
+		if t.is("not") {
+			t = p.next()
+			if !t.is("in") && !t.is("like") {
+				return nil, p.unexpected(t, "in or like")
+			}
+			cond.op = "not " + strings.ToLower(t.text)
+		}
+		if t.is("in") {
+			values, err := p.parseList()
+			if err != nil {
+				return nil, err
+			}
+			cond.values = values
+			break
+		}
+		v := p.next()
+		if v.kind != tokenString {
+			return nil, p.unexpected(v, "pattern string")
+		}
+		cond.values = []interface{}{v.value}
+		cond.like = likePattern(v.text)
+	default:
+		return nil, p.unexpected(t, "operator")
+	}
+	return cond, nil
+}
+
+func (p *conditionParser) parseList() ([]interface{}, error) {
+	if t := p.next(); t.kind != tokenLParen {
+		return nil, p.unexpected(t, "(")
+	}
+	var values []interface{}
+	for {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+
+		t := p.next()
+		if t.kind == tokenRParen {
+			return values, nil
+		}
+		if t.kind != tokenComma {
+			return nil, p.unexpected(t, ", or )")
+		}
+	}
+}
+
+func (p *conditionParser) parseValue() (interface{}, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenString || t.kind == tokenNumber:
+		return t.value, nil
+	case t.is("true"):
+		return true, nil
+	case t.is("false"):
+		return false, nil
+	case t.is("null"):
+		return nil, nil
+	}
+	return nil, p.unexpected(t, "value")
+}
+
+func (p *conditionParser) checkColumn(column string) error {
+	if p.table == nil || strings.HasPrefix(column, "_") {
+		return nil
+	}
+	if p.table.ColumnByName(column) == nil && p.table.ColumnByKey(column) == nil {
+		err := fmt.Errorf("column %s not found in table %s", column, p.table.Name)
+		return err
+	}
+	return nil
+}
+
+func likePattern(s string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for _, r := range s {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func (c *compareCondition) match(r *Row) (bool, error) {
+	var v interface{}
+	var col *Column
+	if strings.HasPrefix(c.column, "_") {
+		v = r.Values[c.column]
+	} else {
+		var err error
+		v, col, err = r.Value(c.column)
+		if err != nil {
+			return false, err
+		}
+	}
+	cells := cellValues(v, col)
+
+	switch c.op {
+	case "is null":
+		return len(cells) == 0, nil
+	case "is not null":
+		return len(cells) > 0, nil
+	case "!=", "not in":
+		return !anyEqual(cells, c.values, col), nil
+	case "in", "=":
+		return anyEqual(cells, c.values, col), nil
+	case "like", "not like":
+		ok := false
+		for _, cell := range cells {
+			if c.like.MatchString(cellText(cell)) {
+				ok = true
+				break
+			}
+		}
+		return ok == (c.op == "like"), nil
+	}
+
+	for _, cell := range cells {
+		n, ok := compareCell(cell, c.values[0], col)
+		if !ok {
+			continue
+		}
+		switch {
+		case c.op == "<" && n < 0, c.op == "<=" && n <= 0,
+			c.op == ">" && n > 0, c.op == ">=" && n >= 0:
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// cellValues returns the values of a cell, none for an empty cell and
+// several for list cells. Select option ids are replaced by their names
+// and links by their display values. An unset checkbox is false, as
+// SeaTable leaves the cell out of rows that were never checked.
+func cellValues(v interface{}, col *Column) []interface{} {
+	var list []interface{}
+	switch c := v.(type) {
+	case nil:
+		if col != nil && col.Type == CHECKBOX {
+			return []interface{}{false}
+		}
+		return nil
+	case string:
+		if c == "" {
+			return nil
+		}
+		list = []interface{}{c}
+	case []interface{}:
+		list = c
+	default:
+		return []interface{}{c}
+	}
+
+	ret := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		switch i := item.(type) {
+		case string:
+			if col != nil {
+				if o := col.OptionByID(i); o != nil {
+					item = o.Name
+				}
+			}
+		case map[string]interface{}:
+			if dv, ok := i["display_value"]; ok {
+				item = dv
+			} else if name, ok := i["name"]; ok {
+				item = name
+			}
+		}
+		if item != nil {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+func anyEqual(cells, values []interface{}, col *Column) bool {
+	for _, cell := range cells {
+		for _, value := range values {
+			if n, ok := compareCell(cell, value, col); ok && n == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func cellText(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// compareCell compares a cell value with a condition value according to
+// the column type and reports whether they are comparable at all.
+func compareCell(cell, value interface{}, col *Column) (int, bool) {
+	if value == nil {
+		return 0, false
+	}
+
+	t := resultType(col)
+	if col == nil {
+		switch cell.(type) {
+		case float64:
+			t = NUMBER
+		case bool:
+			t = CHECKBOX
+		}
+	}
+
+	switch t {
+	case NUMBER, RATING, DURATION:
+		a, err := toFloat(cell)
+		if err != nil {
+			return 0, false
+		}
+		b, err := toFloat(value)
+		if err != nil {
+			return 0, false
+		}
+		return compareFloat(a, b), true
+	case DATE, CTIME, MTIME:
+		s, ok := value.(string)
+		if !ok {
+			return 0, false
+		}
+		a, err := parseTime(cellText(cell))
+		if err != nil {
+			return 0, false
+		}
+		b, err := parseTime(s)
+		if err != nil {
+			return 0, false
+		}
+		if len(s) == len(dateLayout) {
+			a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		switch {
+		case a.Before(b):
+			return -1, true
+		case a.After(b):
+			return 1, true
+		}
+		return 0, true
+	case CHECKBOX:
+		a, ok := cell.(bool)
+		if !ok {
+			return 0, false
+		}
+		b, ok := value.(bool)
+		if !ok {
+			s, _ := value.(string)
+			var err error
+			if b, err = strconv.ParseBool(s); err != nil {
+				return 0, false
+			}
+		}
+		switch {
+		case a == b:
+			return 0, true
+		case b:
+			return -1, true
+		}
+		return 1, true
+	}
+	return strings.Compare(cellText(cell), cellText(value)), true
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
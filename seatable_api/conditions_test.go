@@ -0,0 +1,109 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConditions(t *testing.T) {
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(testRow), &values); err != nil {
+		t.Fatal(err)
+	}
+	table := testTable(t, "table1")
+	row := NewRow(table, values)
+
+	tests := []struct {
+		conditions string
+		want       bool
+	}{
+		{"Name = 'name1'", true},
+		{"Name != 'name1'", false},
+		{"age >= 20 and age < 21", true},
+		{"age > 20", false},
+		{"age > 5 and Name = 'x' or Status in ('Open', 'Done')", true},
+		{"age > 5 and (Name = 'x' or Status = 'Open')", false},
+		{"not Done = false", true},
+		{"Done = 'true'", true},
+		{"Tags = 'blue'", true},
+		{"Tags not in ('red', 'blue')", false},
+		{"Birthday = '2021-03-05'", true},
+		{"Birthday < '2021-03-05 08:00'", true},
+		{"_mtime >= '2021-03-05'", true},
+		{"Name like 'NAME%'", true},
+		{"Name not like '%2'", true},
+		{"Projects = 'Launch'", true},
+		{"Score = 40", true},
+		{"Owner is not null", true},
+		{"Place is null", false},
+		{"`Name` = \"name1\"", true},
+		{"_id = 'Qtf7xPmoRaiFyQPO1aENTjb'", true},
+	}
+	for _, tt := range tests {
+		cond, err := parseConditions(tt.conditions, table)
+		if err != nil {
+			t.Errorf("%s: %v", tt.conditions, err)
+			continue
+		}
+		got, err := cond.match(row)
+		if err != nil || got != tt.want {
+			t.Errorf("%s = %v, %v, want %v", tt.conditions, got, err, tt.want)
+		}
+	}
+}
+
+func TestConditionsUnsetCheckbox(t *testing.T) {
+	table := testTable(t, "table1")
+	rows := []*Row{
+		NewRow(table, map[string]interface{}{"Name": "missing"}),
+		NewRow(table, map[string]interface{}{"Name": "checked", "Done": true}),
+		NewRow(table, map[string]interface{}{"Name": "unchecked", "Done": false}),
+	}
+
+	tests := []struct {
+		conditions string
+		want       int
+	}{
+		{"Done = false", 2},
+		{"Done != true", 2},
+		{"Done = true", 1},
+		{"Done is null", 0},
+	}
+	for _, tt := range tests {
+		cond, err := parseConditions(tt.conditions, table)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.conditions, err)
+		}
+		n := 0
+		for _, row := range rows {
+			ok, err := cond.match(row)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.conditions, err)
+			}
+			if ok {
+				n++
+			}
+		}
+		if n != tt.want {
+			t.Errorf("%s matched %d rows, want %d", tt.conditions, n, tt.want)
+		}
+	}
+}
+
+func TestConditionsInvalid(t *testing.T) {
+	table := testTable(t, "table1")
+	for _, s := range []string{
+		"Name =",
+		"Name = 'x",
+		"Missing = 1",
+		"age > 5 and",
+		"(age > 5",
+		"Status in 'A'",
+		"age ! 5",
+		"Name like 5",
+	} {
+		if _, err := parseConditions(s, table); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
@@ -2,6 +2,134 @@ package seatable_api
 
 type ColumnTypes string
 
+const (
+	TEXT            ColumnTypes = "text"
+	LONG_TEXT       ColumnTypes = "long-text"
+	NUMBER          ColumnTypes = "number"
+	COLLABORATOR    ColumnTypes = "collaborator"
+	DATE            ColumnTypes = "date"
+	DURATION        ColumnTypes = "duration"
+	SINGLE_SELECT   ColumnTypes = "single-select"
+	MULTIPLE_SELECT ColumnTypes = "multiple-select"
+	IMAGE           ColumnTypes = "image"
+	FILE            ColumnTypes = "file"
+	EMAIL           ColumnTypes = "email"
+	URL             ColumnTypes = "url"
+	CHECKBOX        ColumnTypes = "checkbox"
+	RATING          ColumnTypes = "rating"
+	FORMULA         ColumnTypes = "formula"
+	LINK            ColumnTypes = "link"
+	LINK_FORMULA    ColumnTypes = "link-formula"
+	GEOLOCATION     ColumnTypes = "geolocation"
+	AUTO_NUMBER     ColumnTypes = "auto-number"
+	BUTTON          ColumnTypes = "button"
+	CREATOR         ColumnTypes = "creator"
+	CTIME           ColumnTypes = "ctime"
+	LAST_MODIFIER   ColumnTypes = "last-modifier"
+	MTIME           ColumnTypes = "mtime"
+	DIGITAL_SIGN    ColumnTypes = "digital-sign"
+)
+
+var columnTypes = []ColumnTypes{
+	TEXT, LONG_TEXT, NUMBER, COLLABORATOR, DATE, DURATION, SINGLE_SELECT,
+	MULTIPLE_SELECT, IMAGE, FILE, EMAIL, URL, CHECKBOX, RATING, FORMULA,
+	LINK, LINK_FORMULA, GEOLOCATION, AUTO_NUMBER, BUTTON, CREATOR, CTIME,
+	LAST_MODIFIER, MTIME, DIGITAL_SIGN,
+}
+
+func (t ColumnTypes) IsValid() bool {
+	for _, v := range columnTypes {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+type NumberFormat string
+
+const (
+	NUMBER_FORMAT_NUMBER          NumberFormat = "number"
+	NUMBER_FORMAT_PERCENT         NumberFormat = "percent"
+	NUMBER_FORMAT_YUAN            NumberFormat = "yuan"
+	NUMBER_FORMAT_DOLLAR          NumberFormat = "dollar"
+	NUMBER_FORMAT_EURO            NumberFormat = "euro"
+	NUMBER_FORMAT_CUSTOM_CURRENCY NumberFormat = "custom_currency"
+)
+
+type DateFormat string
+
+const (
+	DATE_FORMAT_ISO           DateFormat = "YYYY-MM-DD"
+	DATE_FORMAT_ISO_MINUTE    DateFormat = "YYYY-MM-DD HH:mm"
+	DATE_FORMAT_US            DateFormat = "M/D/YYYY"
+	DATE_FORMAT_US_MINUTE     DateFormat = "M/D/YYYY HH:mm"
+	DATE_FORMAT_EUROPE        DateFormat = "DD.MM.YYYY"
+	DATE_FORMAT_EUROPE_MINUTE DateFormat = "DD.MM.YYYY HH:mm"
+)
+
+type DurationFormat string
+
+const (
+	DURATION_FORMAT_H_MM    DurationFormat = "h:mm"
+	DURATION_FORMAT_H_MM_SS DurationFormat = "h:mm:ss"
+)
+
+type FilterPredicate string
+
+const (
+	FILTER_PREDICATE_CONTAINS           FilterPredicate = "contains"
+	FILTER_PREDICATE_DOES_NOT_CONTAIN   FilterPredicate = "does_not_contain"
+	FILTER_PREDICATE_IS                 FilterPredicate = "is"
+	FILTER_PREDICATE_IS_NOT             FilterPredicate = "is_not"
+	FILTER_PREDICATE_EQUAL              FilterPredicate = "="
+	FILTER_PREDICATE_NOT_EQUAL          FilterPredicate = "≠"
+	FILTER_PREDICATE_LESS               FilterPredicate = "<"
+	FILTER_PREDICATE_GREATER            FilterPredicate = ">"
+	FILTER_PREDICATE_LESS_OR_EQUAL      FilterPredicate = "<="
+	FILTER_PREDICATE_GREATER_OR_EQUAL   FilterPredicate = ">="
+	FILTER_PREDICATE_IS_EMPTY           FilterPredicate = "is_empty"
+	FILTER_PREDICATE_IS_NOT_EMPTY       FilterPredicate = "is_not_empty"
+	FILTER_PREDICATE_IS_WITHIN          FilterPredicate = "is_within"
+	FILTER_PREDICATE_IS_BEFORE          FilterPredicate = "is_before"
+	FILTER_PREDICATE_IS_AFTER           FilterPredicate = "is_after"
+	FILTER_PREDICATE_IS_ON_OR_BEFORE    FilterPredicate = "is_on_or_before"
+	FILTER_PREDICATE_IS_ON_OR_AFTER     FilterPredicate = "is_on_or_after"
+	FILTER_PREDICATE_HAS_ANY_OF         FilterPredicate = "has_any_of"
+	FILTER_PREDICATE_HAS_ALL_OF         FilterPredicate = "has_all_of"
+	FILTER_PREDICATE_HAS_NONE_OF        FilterPredicate = "has_none_of"
+	FILTER_PREDICATE_IS_EXACTLY         FilterPredicate = "is_exactly"
+	FILTER_PREDICATE_IS_ANY_OF          FilterPredicate = "is_any_of"
+	FILTER_PREDICATE_IS_NONE_OF         FilterPredicate = "is_none_of"
+	FILTER_PREDICATE_INCLUDE_ME         FilterPredicate = "include_me"
+	FILTER_PREDICATE_IS_CURRENT_USER_ID FilterPredicate = "is_current_user_ID"
+)
+
+type FilterTermModifier string
+
+const (
+	FILTER_TERM_MODIFIER_TODAY                   FilterTermModifier = "today"
+	FILTER_TERM_MODIFIER_TOMORROW                FilterTermModifier = "tomorrow"
+	FILTER_TERM_MODIFIER_YESTERDAY               FilterTermModifier = "yesterday"
+	FILTER_TERM_MODIFIER_ONE_WEEK_AGO            FilterTermModifier = "one_week_ago"
+	FILTER_TERM_MODIFIER_ONE_WEEK_FROM_NOW       FilterTermModifier = "one_week_from_now"
+	FILTER_TERM_MODIFIER_ONE_MONTH_AGO           FilterTermModifier = "one_month_ago"
+	FILTER_TERM_MODIFIER_ONE_MONTH_FROM_NOW      FilterTermModifier = "one_month_from_now"
+	FILTER_TERM_MODIFIER_NUMBER_OF_DAYS_AGO      FilterTermModifier = "number_of_days_ago"
+	FILTER_TERM_MODIFIER_NUMBER_OF_DAYS_FROM_NOW FilterTermModifier = "number_of_days_from_now"
+	FILTER_TERM_MODIFIER_EXACT_DATE              FilterTermModifier = "exact_date"
+
+	// Modifiers of the is_within predicate.
+	FILTER_TERM_MODIFIER_THE_PAST_WEEK            FilterTermModifier = "the_past_week"
+	FILTER_TERM_MODIFIER_THE_PAST_MONTH           FilterTermModifier = "the_past_month"
+	FILTER_TERM_MODIFIER_THE_PAST_YEAR            FilterTermModifier = "the_past_year"
+	FILTER_TERM_MODIFIER_THE_NEXT_WEEK            FilterTermModifier = "the_next_week"
+	FILTER_TERM_MODIFIER_THE_NEXT_MONTH           FilterTermModifier = "the_next_month"
+	FILTER_TERM_MODIFIER_THE_NEXT_YEAR            FilterTermModifier = "the_next_year"
+	FILTER_TERM_MODIFIER_THE_PAST_NUMBERS_OF_DAYS FilterTermModifier = "the_past_numbers_of_days"
+	FILTER_TERM_MODIFIER_THE_NEXT_NUMBERS_OF_DAYS FilterTermModifier = "the_next_numbers_of_days"
+)
+
 var ROW_FILTER_KEYS []string = []string{"column_name", "filter_predicate", "filter_term", "filter_term_modifier"}
 
 const (
@@ -11,6 +139,7 @@ const (
 	MOVE_COLUMN        = "move_column"
 	MODIFY_COLUMN_TYPE = "modify_column_type"
 	DELETE_COLUMN      = "delete_column"
+	SET_COLUMN_DATA    = "set_column_data"
 
 	JOIN_ROOM        = "join-room"
 	UPDATE_DTABLE    = "update-dtable"
@@ -0,0 +1,87 @@
+package seatable_api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// blockUntilCancelled returns a handler that signals started and then
+// holds the request until the client goes away. The body is read first,
+// as the server only notices a closed connection after that.
+func blockUntilCancelled(started chan<- struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(ioutil.Discard, r.Body)
+		started <- struct{}{}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+// cancelInFlight runs call with a context that is cancelled once a
+// request reaches the handler and returns the error of call.
+func cancelInFlight(t *testing.T, started <-chan struct{}, call func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-started:
+			cancel()
+		case <-time.After(5 * time.Second):
+			t.Errorf("request never reached the stand-in")
+			cancel()
+		}
+	}()
+	return call(ctx)
+}
+
+func TestCtxCancelInFlight(t *testing.T) {
+	si := newStandIn(t)
+	si.handleFiles()
+	started := make(chan struct{})
+	si.handle("/rows/", blockUntilCancelled(started))
+	si.handle(standInUploadPath, blockUntilCancelled(started))
+	si.handle(standInDownloadPath, blockUntilCancelled(started))
+	b := si.base(t)
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{"AppendRowCtx", func(ctx context.Context) error {
+			_, err := b.AppendRowCtx(ctx, tableName, map[string]interface{}{"Name": "name1"})
+			return err
+		}},
+		{"ListRowsCtx", func(ctx context.Context) error {
+			_, err := b.ListRowsCtx(ctx, tableName, "")
+			return err
+		}},
+		{"UploadBytesFileCtx", func(ctx context.Context) error {
+			_, err := b.UploadBytesFileCtx(ctx, "hello.md", bytes.NewReader([]byte("hello world")), "", "file", false)
+			return err
+		}},
+		{"DownloadFileCtx", func(ctx context.Context) error {
+			return b.DownloadFileCtx(ctx, si.fileURL(), filepath.Join(t.TempDir(), "hello.md"))
+		}},
+	}
+
+	for _, tt := range tests {
+		start := time.Now()
+		err := cancelInFlight(t, started, tt.call)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", tt.name, err)
+		}
+		if d := time.Since(start); d > 5*time.Second {
+			t.Errorf("%s: returned after %v", tt.name, d)
+		}
+	}
+}
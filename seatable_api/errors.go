@@ -0,0 +1,96 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrNotFound is wrapped by errors for tables, columns or rows that do
+// not exist in the base.
+var ErrNotFound = errors.New("not found")
+
+// APIError is returned by Base methods when SeaTable answers with an
+// error status. Use errors.As to inspect it.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Message    string
+	Body       []byte
+}
+
+func newAPIError(method, url string, status int, body []byte) *APIError {
+	return &APIError{
+		StatusCode: status,
+		Method:     method,
+		URL:        url,
+		Message:    parseErrorMessage(body),
+		Body:       body,
+	}
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("bad response for %s %s: %d: %s", e.Method, e.URL, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("bad response for %s %s: %d", e.Method, e.URL, e.StatusCode)
+}
+
+func parseErrorMessage(body []byte) string {
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return ""
+	}
+
+	for _, key := range []string{"error_msg", "error_message", "detail", "error"} {
+		if msg, ok := data[key].(string); ok && msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// RowNotFoundError is returned by GetRow and GetRows for rows that do
+// not exist. It wraps ErrNotFound.
+type RowNotFoundError struct {
+	Table  string
+	RowIDs []string
+}
+
+func (e *RowNotFoundError) Error() string {
+	return fmt.Sprintf("rows %s not found in table %s", strings.Join(e.RowIDs, ", "), e.Table)
+}
+
+func (e *RowNotFoundError) Unwrap() error {
+	return ErrNotFound
+}
+
+func hasStatus(err error, status int) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.StatusCode == status
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || hasStatus(err, http.StatusNotFound)
+}
+
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether err is a 429 response from SeaTable or
+// a *RateLimitError from the client-side limiter.
+func IsRateLimited(err error) bool {
+	var e *RateLimitError
+	if errors.As(err, &e) {
+		return true
+	}
+	return hasStatus(err, http.StatusTooManyRequests)
+}
@@ -0,0 +1,95 @@
+package seatable_api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestParseErrorMessage(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"error_msg": "table not found"}`, "table not found"},
+		{`{"error_message": "row not found"}`, "row not found"},
+		{`{"detail": "Token expired"}`, "Token expired"},
+		{`{"error": "bad request"}`, "bad request"},
+		{`{"error_msg": "", "detail": "fallback"}`, "fallback"},
+		{`{"error_msg": 42}`, ""},
+		{`["a"]`, ""},
+		{`<html>Bad Gateway</html>`, ""},
+		{``, ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseErrorMessage([]byte(tt.body)); got != tt.want {
+			t.Errorf("parseErrorMessage(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestAPIErrorString(t *testing.T) {
+	err := newAPIError("GET", "https://example.com/rows/", 404, []byte(`{"error_msg": "table not found"}`))
+	if got := err.Error(); got != "bad response for GET https://example.com/rows/: 404: table not found" {
+		t.Errorf("unexpected error %q", got)
+	}
+
+	err = newAPIError("POST", "https://example.com/rows/", 502, []byte(`Bad Gateway`))
+	if got := err.Error(); got != "bad response for POST https://example.com/rows/: 502" {
+		t.Errorf("unexpected error %q", got)
+	}
+}
+
+func TestAPIErrorFromMethods(t *testing.T) {
+	si := newStandIn(t)
+	var status int
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		writeJSON(w, map[string]interface{}{"error_msg": http.StatusText(status)})
+	})
+	b := si.base(t, WithRetryPolicy(RetryPolicy{}))
+
+	tests := []struct {
+		status int
+		is     func(error) bool
+	}{
+		{http.StatusNotFound, IsNotFound},
+		{http.StatusUnauthorized, IsUnauthorized},
+		{http.StatusForbidden, IsForbidden},
+		{http.StatusTooManyRequests, IsRateLimited},
+	}
+	helpers := []func(error) bool{IsNotFound, IsUnauthorized, IsForbidden, IsRateLimited}
+
+	for _, tt := range tests {
+		status = tt.status
+		_, err := b.AppendRow(tableName, map[string]interface{}{"Name": "name1"})
+		err = fmt.Errorf("wrapped by caller: %w", err)
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("%d: no APIError in %v", tt.status, err)
+			continue
+		}
+		if apiErr.StatusCode != tt.status || apiErr.Method != "POST" || apiErr.Message != http.StatusText(tt.status) {
+			t.Errorf("%d: unexpected error %+v", tt.status, apiErr)
+		}
+
+		matched := 0
+		for _, is := range helpers {
+			if is(err) {
+				matched++
+			}
+		}
+		if !tt.is(err) || matched != 1 {
+			t.Errorf("%d: matched by %d helpers", tt.status, matched)
+		}
+	}
+
+	// Request errors wrapping ErrNotFound are not found errors too.
+	err := fmt.Errorf("column x in table y: %w", ErrNotFound)
+	if !IsNotFound(err) || !IsNotFound(&RowNotFoundError{Table: "y", RowIDs: []string{"a"}}) || IsNotFound(errors.New("other")) {
+		t.Error("IsNotFound does not match ErrNotFound")
+	}
+}
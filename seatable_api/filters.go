@@ -0,0 +1,503 @@
+package seatable_api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Filter starts the filters passed to FilterRowsBy:
+//
+//	f := Filter.Column("age").GreaterThan(10).And(
+//		Filter.Column("Status").IsAnyOf("Open", "Doing"),
+//		Filter.Column("Birthday").IsBefore(DaysAgo(30)),
+//	)
+//
+// The filters are checked against the column types of the table before
+// they are sent. The API combines all filters with a single conjunction,
+// so And and Or can not be mixed.
+var Filter FilterBuilder
+
+type FilterBuilder struct{}
+
+func (FilterBuilder) Column(name string) *ColumnFilter {
+	return &ColumnFilter{column: name}
+}
+
+// ColumnFilter creates a single filter on a column. Select options are
+// given by name and translated to their ids when the filters are built.
+type ColumnFilter struct {
+	column string
+}
+
+type filterTerm struct {
+	column    string
+	predicate FilterPredicate
+	term      interface{}
+}
+
+// Filters is a list of filters with their conjunction.
+type Filters struct {
+	terms       []*filterTerm
+	conjunction string
+	err         error
+}
+
+// DateTerm is the term of a date filter: a modifier such as
+// FILTER_TERM_MODIFIER_TODAY and, for some modifiers, a value.
+type DateTerm struct {
+	Modifier FilterTermModifier
+	Value    interface{}
+}
+
+func RelativeDate(modifier FilterTermModifier) DateTerm {
+	return DateTerm{Modifier: modifier}
+}
+
+func ExactDate(t time.Time) DateTerm {
+	return DateTerm{Modifier: FILTER_TERM_MODIFIER_EXACT_DATE, Value: t.Format(dateLayout)}
+}
+
+func DaysAgo(days int) DateTerm {
+	return DateTerm{Modifier: FILTER_TERM_MODIFIER_NUMBER_OF_DAYS_AGO, Value: days}
+}
+
+func DaysFromNow(days int) DateTerm {
+	return DateTerm{Modifier: FILTER_TERM_MODIFIER_NUMBER_OF_DAYS_FROM_NOW, Value: days}
+}
+
+// PastDays and NextDays are terms of IsWithin.
+func PastDays(days int) DateTerm {
+	return DateTerm{Modifier: FILTER_TERM_MODIFIER_THE_PAST_NUMBERS_OF_DAYS, Value: days}
+}
+
+func NextDays(days int) DateTerm {
+	return DateTerm{Modifier: FILTER_TERM_MODIFIER_THE_NEXT_NUMBERS_OF_DAYS, Value: days}
+}
+
+func (c *ColumnFilter) filter(predicate FilterPredicate, term interface{}) *Filters {
+	return &Filters{terms: []*filterTerm{{column: c.column, predicate: predicate, term: term}}}
+}
+
+func (c *ColumnFilter) Contains(s string) *Filters {
+	return c.filter(FILTER_PREDICATE_CONTAINS, s)
+}
+
+func (c *ColumnFilter) DoesNotContain(s string) *Filters {
+	return c.filter(FILTER_PREDICATE_DOES_NOT_CONTAIN, s)
+}
+
+// Is takes a string for text and single-select columns, a bool for
+// checkbox columns and a DateTerm for date columns.
+func (c *ColumnFilter) Is(v interface{}) *Filters {
+	return c.filter(FILTER_PREDICATE_IS, v)
+}
+
+func (c *ColumnFilter) IsNot(v interface{}) *Filters {
+	return c.filter(FILTER_PREDICATE_IS_NOT, v)
+}
+
+func (c *ColumnFilter) Equal(n float64) *Filters {
+	return c.filter(FILTER_PREDICATE_EQUAL, n)
+}
+
+func (c *ColumnFilter) NotEqual(n float64) *Filters {
+	return c.filter(FILTER_PREDICATE_NOT_EQUAL, n)
+}
+
+func (c *ColumnFilter) LessThan(n float64) *Filters {
+	return c.filter(FILTER_PREDICATE_LESS, n)
+}
+
+func (c *ColumnFilter) GreaterThan(n float64) *Filters {
+	return c.filter(FILTER_PREDICATE_GREATER, n)
+}
+
+func (c *ColumnFilter) LessOrEqual(n float64) *Filters {
+	return c.filter(FILTER_PREDICATE_LESS_OR_EQUAL, n)
+}
+
+func (c *ColumnFilter) GreaterOrEqual(n float64) *Filters {
+	return c.filter(FILTER_PREDICATE_GREATER_OR_EQUAL, n)
+}
+
+func (c *ColumnFilter) IsEmpty() *Filters {
+	return c.filter(FILTER_PREDICATE_IS_EMPTY, nil)
+}
+
+func (c *ColumnFilter) IsNotEmpty() *Filters {
+	return c.filter(FILTER_PREDICATE_IS_NOT_EMPTY, nil)
+}
+
+func (c *ColumnFilter) IsWithin(d DateTerm) *Filters {
+	return c.filter(FILTER_PREDICATE_IS_WITHIN, d)
+}
+
+func (c *ColumnFilter) IsBefore(d DateTerm) *Filters {
+	return c.filter(FILTER_PREDICATE_IS_BEFORE, d)
+}
+
+func (c *ColumnFilter) IsAfter(d DateTerm) *Filters {
+	return c.filter(FILTER_PREDICATE_IS_AFTER, d)
+}
+
+func (c *ColumnFilter) IsOnOrBefore(d DateTerm) *Filters {
+	return c.filter(FILTER_PREDICATE_IS_ON_OR_BEFORE, d)
+}
+
+func (c *ColumnFilter) IsOnOrAfter(d DateTerm) *Filters {
+	return c.filter(FILTER_PREDICATE_IS_ON_OR_AFTER, d)
+}
+
+func (c *ColumnFilter) HasAnyOf(values ...string) *Filters {
+	return c.filter(FILTER_PREDICATE_HAS_ANY_OF, values)
+}
+
+func (c *ColumnFilter) HasAllOf(values ...string) *Filters {
+	return c.filter(FILTER_PREDICATE_HAS_ALL_OF, values)
+}
+
+func (c *ColumnFilter) HasNoneOf(values ...string) *Filters {
+	return c.filter(FILTER_PREDICATE_HAS_NONE_OF, values)
+}
+
+func (c *ColumnFilter) IsExactly(values ...string) *Filters {
+	return c.filter(FILTER_PREDICATE_IS_EXACTLY, values)
+}
+
+func (c *ColumnFilter) IsAnyOf(values ...string) *Filters {
+	return c.filter(FILTER_PREDICATE_IS_ANY_OF, values)
+}
+
+func (c *ColumnFilter) IsNoneOf(values ...string) *Filters {
+	return c.filter(FILTER_PREDICATE_IS_NONE_OF, values)
+}
+
+func (c *ColumnFilter) IncludeMe() *Filters {
+	return c.filter(FILTER_PREDICATE_INCLUDE_ME, nil)
+}
+
+func (c *ColumnFilter) IsCurrentUserID() *Filters {
+	return c.filter(FILTER_PREDICATE_IS_CURRENT_USER_ID, nil)
+}
+
+func (f *Filters) And(others ...*Filters) *Filters {
+	return f.combine("And", others)
+}
+
+func (f *Filters) Or(others ...*Filters) *Filters {
+	return f.combine("Or", others)
+}
+
+func (f *Filters) combine(conjunction string, others []*Filters) *Filters {
+	ret := &Filters{conjunction: f.conjunction, err: f.err}
+	ret.terms = append(ret.terms, f.terms...)
+	for _, o := range append([]*Filters{f}, others...) {
+		if o == nil {
+			ret.err = fmt.Errorf("filters can not be nil")
+			continue
+		}
+		if o.err != nil && ret.err == nil {
+			ret.err = o.err
+		}
+		if o.conjunction != "" && o.conjunction != conjunction && len(o.terms) > 1 {
+			ret.err = fmt.Errorf("can not combine %s and %s filters, the API supports a single conjunction", o.conjunction, conjunction)
+		}
+		if o != f {
+			ret.terms = append(ret.terms, o.terms...)
+		}
+	}
+	ret.conjunction = conjunction
+	return ret
+}
+
+// Conjunction returns the filter_conjunction of the filters.
+func (f *Filters) Conjunction() string {
+	if f.conjunction == "" {
+		return "And"
+	}
+	return f.conjunction
+}
+
+type filterTermKind int
+
+const (
+	termNone filterTermKind = iota
+	termString
+	termNumber
+	termBool
+	termDate
+	termList
+)
+
+var textPredicates = []FilterPredicate{
+	FILTER_PREDICATE_CONTAINS, FILTER_PREDICATE_DOES_NOT_CONTAIN, FILTER_PREDICATE_IS,
+	FILTER_PREDICATE_IS_NOT, FILTER_PREDICATE_IS_EMPTY, FILTER_PREDICATE_IS_NOT_EMPTY,
+	FILTER_PREDICATE_IS_CURRENT_USER_ID,
+}
+
+var numberPredicates = []FilterPredicate{
+	FILTER_PREDICATE_EQUAL, FILTER_PREDICATE_NOT_EQUAL, FILTER_PREDICATE_LESS,
+	FILTER_PREDICATE_GREATER, FILTER_PREDICATE_LESS_OR_EQUAL, FILTER_PREDICATE_GREATER_OR_EQUAL,
+	FILTER_PREDICATE_IS_EMPTY, FILTER_PREDICATE_IS_NOT_EMPTY,
+}
+
+var datePredicates = []FilterPredicate{
+	FILTER_PREDICATE_IS, FILTER_PREDICATE_IS_NOT, FILTER_PREDICATE_IS_BEFORE,
+	FILTER_PREDICATE_IS_AFTER, FILTER_PREDICATE_IS_ON_OR_BEFORE, FILTER_PREDICATE_IS_ON_OR_AFTER,
+	FILTER_PREDICATE_IS_WITHIN, FILTER_PREDICATE_IS_EMPTY, FILTER_PREDICATE_IS_NOT_EMPTY,
+}
+
+var listPredicates = []FilterPredicate{
+	FILTER_PREDICATE_HAS_ANY_OF, FILTER_PREDICATE_HAS_ALL_OF, FILTER_PREDICATE_HAS_NONE_OF,
+	FILTER_PREDICATE_IS_EXACTLY, FILTER_PREDICATE_IS_EMPTY, FILTER_PREDICATE_IS_NOT_EMPTY,
+}
+
+var emptyPredicates = []FilterPredicate{
+	FILTER_PREDICATE_IS_EMPTY, FILTER_PREDICATE_IS_NOT_EMPTY,
+}
+
+// filterPredicates lists the predicates supported by each column type;
+// formula columns use the predicates of their result type.
+var filterPredicates = map[ColumnTypes][]FilterPredicate{
+	TEXT:        textPredicates,
+	LONG_TEXT:   textPredicates,
+	EMAIL:       textPredicates,
+	URL:         textPredicates,
+	AUTO_NUMBER: textPredicates,
+	NUMBER:      numberPredicates,
+	RATING:      numberPredicates,
+	DURATION:    numberPredicates,
+	DATE:        datePredicates,
+	CTIME:       datePredicates,
+	MTIME:       datePredicates,
+	CHECKBOX:    {FILTER_PREDICATE_IS},
+	SINGLE_SELECT: {
+		FILTER_PREDICATE_IS, FILTER_PREDICATE_IS_NOT, FILTER_PREDICATE_IS_ANY_OF,
+		FILTER_PREDICATE_IS_NONE_OF, FILTER_PREDICATE_IS_EMPTY, FILTER_PREDICATE_IS_NOT_EMPTY,
+	},
+	MULTIPLE_SELECT: listPredicates,
+	LINK:            listPredicates,
+	COLLABORATOR:    append([]FilterPredicate{FILTER_PREDICATE_INCLUDE_ME}, listPredicates...),
+	CREATOR: {
+		FILTER_PREDICATE_CONTAINS, FILTER_PREDICATE_DOES_NOT_CONTAIN, FILTER_PREDICATE_INCLUDE_ME,
+	},
+	LAST_MODIFIER: {
+		FILTER_PREDICATE_CONTAINS, FILTER_PREDICATE_DOES_NOT_CONTAIN, FILTER_PREDICATE_INCLUDE_ME,
+	},
+	GEOLOCATION: {
+		FILTER_PREDICATE_CONTAINS, FILTER_PREDICATE_DOES_NOT_CONTAIN,
+		FILTER_PREDICATE_IS_EMPTY, FILTER_PREDICATE_IS_NOT_EMPTY,
+	},
+	FORMULA:      emptyPredicates,
+	LINK_FORMULA: emptyPredicates,
+	IMAGE:        emptyPredicates,
+	FILE:         emptyPredicates,
+	DIGITAL_SIGN: emptyPredicates,
+}
+
+var withinModifiers = []FilterTermModifier{
+	FILTER_TERM_MODIFIER_THE_PAST_WEEK, FILTER_TERM_MODIFIER_THE_PAST_MONTH,
+	FILTER_TERM_MODIFIER_THE_PAST_YEAR, FILTER_TERM_MODIFIER_THE_NEXT_WEEK,
+	FILTER_TERM_MODIFIER_THE_NEXT_MONTH, FILTER_TERM_MODIFIER_THE_NEXT_YEAR,
+	FILTER_TERM_MODIFIER_THE_PAST_NUMBERS_OF_DAYS, FILTER_TERM_MODIFIER_THE_NEXT_NUMBERS_OF_DAYS,
+}
+
+var dateModifiers = []FilterTermModifier{
+	FILTER_TERM_MODIFIER_TODAY, FILTER_TERM_MODIFIER_TOMORROW, FILTER_TERM_MODIFIER_YESTERDAY,
+	FILTER_TERM_MODIFIER_ONE_WEEK_AGO, FILTER_TERM_MODIFIER_ONE_WEEK_FROM_NOW,
+	FILTER_TERM_MODIFIER_ONE_MONTH_AGO, FILTER_TERM_MODIFIER_ONE_MONTH_FROM_NOW,
+	FILTER_TERM_MODIFIER_NUMBER_OF_DAYS_AGO, FILTER_TERM_MODIFIER_NUMBER_OF_DAYS_FROM_NOW,
+	FILTER_TERM_MODIFIER_EXACT_DATE,
+}
+
+// valueModifiers need a DateTerm value.
+var valueModifiers = []FilterTermModifier{
+	FILTER_TERM_MODIFIER_NUMBER_OF_DAYS_AGO, FILTER_TERM_MODIFIER_NUMBER_OF_DAYS_FROM_NOW,
+	FILTER_TERM_MODIFIER_EXACT_DATE, FILTER_TERM_MODIFIER_THE_PAST_NUMBERS_OF_DAYS,
+	FILTER_TERM_MODIFIER_THE_NEXT_NUMBERS_OF_DAYS,
+}
+
+func hasPredicate(list []FilterPredicate, p FilterPredicate) bool {
+	for _, v := range list {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
+
+func hasModifier(list []FilterTermModifier, m FilterTermModifier) bool {
+	for _, v := range list {
+		if v == m {
+			return true
+		}
+	}
+	return false
+}
+
+func termKind(t ColumnTypes, p FilterPredicate) filterTermKind {
+	switch p {
+	case FILTER_PREDICATE_IS_EMPTY, FILTER_PREDICATE_IS_NOT_EMPTY,
+		FILTER_PREDICATE_INCLUDE_ME, FILTER_PREDICATE_IS_CURRENT_USER_ID:
+		return termNone
+	case FILTER_PREDICATE_HAS_ANY_OF, FILTER_PREDICATE_HAS_ALL_OF, FILTER_PREDICATE_HAS_NONE_OF,
+		FILTER_PREDICATE_IS_EXACTLY, FILTER_PREDICATE_IS_ANY_OF, FILTER_PREDICATE_IS_NONE_OF:
+		return termList
+	}
+	switch t {
+	case NUMBER, RATING, DURATION:
+		return termNumber
+	case DATE, CTIME, MTIME:
+		return termDate
+	case CHECKBOX:
+		return termBool
+	}
+	return termString
+}
+
+// Build checks the filters against the columns of table and returns them
+// in the form expected by FilterRows.
+func (f *Filters) Build(table *Table) ([]map[string]interface{}, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	ret := make([]map[string]interface{}, 0, len(f.terms))
+	for _, t := range f.terms {
+		filter, err := t.build(table)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, filter)
+	}
+	return ret, nil
+}
+
+func (t *filterTerm) build(table *Table) (map[string]interface{}, error) {
+	col := table.ColumnByName(t.column)
+	if col == nil {
+		col = table.ColumnByKey(t.column)
+	}
+	if col == nil {
+		err := fmt.Errorf("column %s not found in table %s", t.column, table.Name)
+		return nil, err
+	}
+
+	colType := resultType(col)
+	if !hasPredicate(filterPredicates[colType], t.predicate) {
+		err := fmt.Errorf("filter predicate %s is not supported by column %s of type %s", t.predicate, col.Name, colType)
+		return nil, err
+	}
+
+	filter := make(map[string]interface{})
+	filter["column_name"] = col.Name
+	filter["filter_predicate"] = string(t.predicate)
+
+	invalid := func(want string) error {
+		return fmt.Errorf("filter %s on column %s expects %s, got %T", t.predicate, col.Name, want, t.term)
+	}
+
+	switch termKind(colType, t.predicate) {
+	case termNone:
+	case termNumber:
+		n, ok := t.term.(float64)
+		if !ok {
+			return nil, invalid("a number")
+		}
+		filter["filter_term"] = n
+	case termBool:
+		b, ok := t.term.(bool)
+		if !ok {
+			return nil, invalid("a bool")
+		}
+		filter["filter_term"] = b
+	case termString:
+		s, ok := t.term.(string)
+		if !ok {
+			return nil, invalid("a string")
+		}
+		if colType == SINGLE_SELECT {
+			id, err := optionID(col, s)
+			if err != nil {
+				return nil, err
+			}
+			s = id
+		}
+		filter["filter_term"] = s
+	case termList:
+		list, ok := t.term.([]string)
+		if !ok {
+			return nil, invalid("a list of strings")
+		}
+		terms := make([]string, 0, len(list))
+		for _, s := range list {
+			if colType == SINGLE_SELECT || colType == MULTIPLE_SELECT {
+				id, err := optionID(col, s)
+				if err != nil {
+					return nil, err
+				}
+				s = id
+			}
+			terms = append(terms, s)
+		}
+		filter["filter_term"] = terms
+	case termDate:
+		d, ok := t.term.(DateTerm)
+		if !ok {
+			return nil, invalid("a DateTerm")
+		}
+		modifiers := dateModifiers
+		if t.predicate == FILTER_PREDICATE_IS_WITHIN {
+			modifiers = withinModifiers
+		}
+		if !hasModifier(modifiers, d.Modifier) {
+			err := fmt.Errorf("filter term modifier %s can not be used with %s on column %s", d.Modifier, t.predicate, col.Name)
+			return nil, err
+		}
+		if hasModifier(valueModifiers, d.Modifier) && d.Value == nil {
+			err := fmt.Errorf("filter term modifier %s on column %s needs a value", d.Modifier, col.Name)
+			return nil, err
+		}
+		filter["filter_term_modifier"] = string(d.Modifier)
+		if d.Value != nil {
+			filter["filter_term"] = d.Value
+		}
+	}
+	return filter, nil
+}
+
+func optionID(col *Column, name string) (string, error) {
+	if o := col.OptionByName(name); o != nil {
+		return o.ID, nil
+	}
+	if o := col.OptionByID(name); o != nil {
+		return o.ID, nil
+	}
+	err := fmt.Errorf("option %s not found in column %s", name, col.Name)
+	return "", err
+}
+
+// FilterRowsBy returns the rows of a table matching filters, which are
+// checked against the table metadata first.
+func (s *Base) FilterRowsBy(tableName, viewName string, filters *Filters) (interface{}, error) {
+	return s.FilterRowsByCtx(context.Background(), tableName, viewName, filters)
+}
+
+func (s *Base) FilterRowsByCtx(ctx context.Context, tableName, viewName string, filters *Filters) (interface{}, error) {
+	if filters == nil {
+		err := fmt.Errorf("filters can not be empty")
+		return nil, err
+	}
+
+	table, err := s.GetTableCtx(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := filters.Build(table)
+	if err != nil {
+		return nil, err
+	}
+	return s.FilterRowsCtx(ctx, tableName, list, viewName, filters.Conjunction())
+}
@@ -0,0 +1,108 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFiltersBuild(t *testing.T) {
+	table := testTable(t, "table1")
+
+	f := Filter.Column("age").GreaterThan(10).And(
+		Filter.Column("Status").IsAnyOf("Open", "Done"),
+		Filter.Column("Birthday").IsBefore(DaysAgo(30)),
+		Filter.Column("Birthday").IsWithin(RelativeDate(FILTER_TERM_MODIFIER_THE_PAST_YEAR)),
+		Filter.Column("Tags").HasAllOf("red"),
+		Filter.Column("Done").Is(true),
+		Filter.Column("Score").GreaterOrEqual(3),
+		Filter.Column("Photos").IsNotEmpty(),
+	)
+	got, err := f.Build(table)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"column_name": "age", "filter_predicate": ">", "filter_term": 10.0},
+		{"column_name": "Status", "filter_predicate": "is_any_of", "filter_term": []string{"111111", "222222"}},
+		{"column_name": "Birthday", "filter_predicate": "is_before", "filter_term_modifier": "number_of_days_ago", "filter_term": 30},
+		{"column_name": "Birthday", "filter_predicate": "is_within", "filter_term_modifier": "the_past_year"},
+		{"column_name": "Tags", "filter_predicate": "has_all_of", "filter_term": []string{"333333"}},
+		{"column_name": "Done", "filter_predicate": "is", "filter_term": true},
+		{"column_name": "Score", "filter_predicate": ">=", "filter_term": 3.0},
+		{"column_name": "Photos", "filter_predicate": "is_not_empty"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if f.Conjunction() != "And" {
+		t.Errorf("unexpected conjunction %s", f.Conjunction())
+	}
+
+	exact, err := Filter.Column("Birthday").Is(ExactDate(time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC))).Build(table)
+	if err != nil || exact[0]["filter_term"] != "2021-03-05" {
+		t.Errorf("exact date = %v, %v", exact, err)
+	}
+}
+
+func TestFiltersInvalid(t *testing.T) {
+	table := testTable(t, "table1")
+
+	for name, f := range map[string]*Filters{
+		"unknown column":     Filter.Column("agee").GreaterThan(10),
+		"text predicate":     Filter.Column("age").Contains("1"),
+		"number predicate":   Filter.Column("Name").GreaterThan(1),
+		"string term":        Filter.Column("Name").Is(1),
+		"checkbox term":      Filter.Column("Done").Is("yes"),
+		"unknown option":     Filter.Column("Status").Is("Closed"),
+		"date term":          Filter.Column("Birthday").Is("2021-03-05"),
+		"within modifier":    Filter.Column("Birthday").IsWithin(RelativeDate(FILTER_TERM_MODIFIER_TODAY)),
+		"missing date value": Filter.Column("Birthday").IsAfter(RelativeDate(FILTER_TERM_MODIFIER_EXACT_DATE)),
+		"mixed conjunction": Filter.Column("age").GreaterThan(1).And(Filter.Column("age").LessThan(5)).
+			Or(Filter.Column("Name").IsEmpty()),
+	} {
+		if _, err := f.Build(table); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	f := Filter.Column("Name").IsEmpty().Or(Filter.Column("age").IsEmpty(), Filter.Column("Tags").IsEmpty())
+	if _, err := f.Build(table); err != nil || f.Conjunction() != "Or" {
+		t.Errorf("or filters = %v, %s", err, f.Conjunction())
+	}
+}
+
+func TestFilterRowsBy(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMetadata))
+	})
+	si.handle("/filtered-rows/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Filters     []map[string]interface{} `json:"filters"`
+			Conjunction string                   `json:"filter_conjunction"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.Filters) != 2 || req.Conjunction != "Or" || req.Filters[1]["filter_term"] != "222222" {
+			t.Errorf("unexpected request %+v", req)
+		}
+		writeJSON(w, map[string]interface{}{"rows": []interface{}{map[string]interface{}{"_id": "r1"}}})
+	})
+	b := si.base(t)
+
+	rows, err := b.FilterRowsBy("table1", "", Filter.Column("Name").Contains("a").Or(Filter.Column("Status").Is("Done")))
+	if err != nil {
+		t.Fatalf("filter failed: %v", err)
+	}
+	if list, _ := rows.([]interface{}); len(list) != 1 {
+		t.Errorf("unexpected rows %v", rows)
+	}
+
+	_, err = b.FilterRowsBy("table1", "", Filter.Column("Missing").IsEmpty())
+	if err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+}
@@ -0,0 +1,146 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const standInUUID = "0c2f6d5a-5a3e-4b0e-9a59-d1e2c3b4a5f6"
+
+// standIn is a local stand-in for dtable-web and dtable-server. Requests
+// to the app-access-token endpoint hand out a fresh access token; other
+// requests must carry an issued, unrevoked token and are passed to the
+// handler registered for their path below /api/v1/dtables/<uuid>, or
+// for their full path when outside dtable-server.
+type standIn struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	auths    int
+	tokens   map[string]bool
+	handlers map[string]http.HandlerFunc
+}
+
+func newStandIn(t *testing.T) *standIn {
+	si := &standIn{
+		tokens:   make(map[string]bool),
+		handlers: make(map[string]http.HandlerFunc),
+	}
+	si.Server = httptest.NewServer(http.HandlerFunc(si.serveHTTP))
+	t.Cleanup(si.Close)
+	return si
+}
+
+func (si *standIn) handle(path string, h http.HandlerFunc) {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	si.handlers[path] = h
+}
+
+// revokeTokens invalidates all access tokens handed out so far.
+func (si *standIn) revokeTokens() {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	si.tokens = make(map[string]bool)
+}
+
+func (si *standIn) authCount() int {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	return si.auths
+}
+
+func (si *standIn) base(t *testing.T, opts ...Option) *Base {
+	b := Init("api-token", si.URL, opts...)
+	if err := b.Auth(false); err != nil {
+		t.Fatalf("failed to auth against stand-in: %v", err)
+	}
+	return b
+}
+
+func (si *standIn) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v2.1/dtable/app-access-token/" {
+		if r.Header.Get("Authorization") != "Token api-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		si.mu.Lock()
+		si.auths++
+		token := fmt.Sprintf("jwt-%d", si.auths)
+		si.tokens[token] = true
+		si.mu.Unlock()
+
+		writeJSON(w, map[string]interface{}{
+			"access_token":  token,
+			"dtable_uuid":   standInUUID,
+			"dtable_server": si.URL + "/dtable-server/",
+			"dtable_db":     si.URL + "/dtable-db/",
+			"workspace_id":  "1",
+			"dtable_name":   "stand-in",
+		})
+		return
+	}
+
+	si.mu.Lock()
+	valid := si.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Token ")]
+	h := si.handlers[strings.TrimPrefix(r.URL.Path, "/dtable-server/api/v1/dtables/"+standInUUID)]
+	si.mu.Unlock()
+
+	if !valid {
+		w.WriteHeader(http.StatusUnauthorized)
+		writeJSON(w, map[string]interface{}{"detail": "Token expired"})
+		return
+	}
+	if h == nil {
+		w.WriteHeader(http.StatusNotFound)
+		writeJSON(w, map[string]interface{}{"error_msg": "not found"})
+		return
+	}
+	h(w, r)
+}
+
+// Paths of the file server behind the upload and download links handed
+// out by handleFiles.
+const (
+	standInUploadPath   = "/seafhttp/upload-api/0a1b"
+	standInDownloadPath = "/seafhttp/files/2c3d/hello.md"
+)
+
+// fileURL is the asset url of the file served by handleFiles.
+func (si *standIn) fileURL() string {
+	return si.URL + "/workspace/1/asset/" + standInUUID + "/files/2021-01/hello.md"
+}
+
+// handleFiles serves the upload and download link endpoints of dtable-web
+// and a file server accepting uploads and serving "hello world".
+func (si *standIn) handleFiles() {
+	si.handle("/api/v2.1/dtable/app-upload-link/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"upload_link": si.URL + standInUploadPath,
+			"parent_path": "/asset/" + standInUUID,
+		})
+	})
+	si.handle(standInUploadPath, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, []interface{}{map[string]interface{}{"name": "hello.md", "size": 11}})
+	})
+	si.handle("/api/v2.1/dtable/app-download-link/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"download_link": si.URL + standInDownloadPath})
+	})
+	si.handle(standInDownloadPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello world"))
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
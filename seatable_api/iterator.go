@@ -0,0 +1,170 @@
+package seatable_api
+
+import (
+	"context"
+	neturl "net/url"
+	"strconv"
+)
+
+// defaultPageSize is the number of rows the server returns at most for
+// a single request.
+const defaultPageSize = 1000
+
+type ListRowsOptions struct {
+	// Start is the offset of the first row, Limit the number of rows to
+	// return. A zero Limit returns as many rows as the server sends for
+	// one request; IterateRows pages through all of them.
+	Start int
+	Limit int
+	// OrderBy is a column name, Direction either "asc" or "desc".
+	OrderBy   string
+	Direction string
+	// ConvertLinkID returns link cells as display values instead of
+	// row ids.
+	ConvertLinkID bool
+}
+
+func (opts *ListRowsOptions) encode(params neturl.Values) {
+	if opts == nil {
+		return
+	}
+	if opts.Start > 0 {
+		params.Add("start", strconv.Itoa(opts.Start))
+	}
+	if opts.Limit > 0 {
+		params.Add("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.OrderBy != "" {
+		params.Add("order_by", opts.OrderBy)
+	}
+	if opts.Direction != "" {
+		params.Add("direction", opts.Direction)
+	}
+	if opts.ConvertLinkID {
+		params.Add("convert_link_id", "true")
+	}
+}
+
+// RowIterator pages through the rows of a table:
+//
+//	it := base.IterateRows("Customers", "", nil)
+//	for it.Next() {
+//		name, _ := it.Row().String("Name")
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type RowIterator struct {
+	base      *Base
+	ctx       context.Context
+	tableName string
+	viewName  string
+	opts      ListRowsOptions
+	pageSize  int
+	remaining int
+
+	table *Table
+	page  []*Row
+	row   *Row
+	done  bool
+	err   error
+}
+
+// IterateRows returns an iterator over the rows of a table. opts.Limit
+// bounds the total number of rows; PageSize rows are fetched at a time.
+func (s *Base) IterateRows(tableName, viewName string, opts *ListRowsOptions) *RowIterator {
+	return s.IterateRowsCtx(context.Background(), tableName, viewName, opts)
+}
+
+func (s *Base) IterateRowsCtx(ctx context.Context, tableName, viewName string, opts *ListRowsOptions) *RowIterator {
+	it := &RowIterator{
+		base:      s,
+		ctx:       ctx,
+		tableName: tableName,
+		viewName:  viewName,
+		pageSize:  defaultPageSize,
+		remaining: -1,
+	}
+	if opts != nil {
+		it.opts = *opts
+		if opts.Limit > 0 {
+			it.remaining = opts.Limit
+		}
+	}
+	return it
+}
+
+// PageSize sets the number of rows requested at a time, at most the
+// 1000 rows the server returns for a single request.
+func (it *RowIterator) PageSize(n int) *RowIterator {
+	if n > defaultPageSize {
+		n = defaultPageSize
+	}
+	if n > 0 {
+		it.pageSize = n
+	}
+	return it
+}
+
+func (it *RowIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 && !it.done {
+		it.fetch()
+	}
+	if len(it.page) == 0 {
+		it.row = nil
+		return false
+	}
+
+	it.row = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+func (it *RowIterator) Row() *Row {
+	return it.row
+}
+
+func (it *RowIterator) Err() error {
+	return it.err
+}
+
+func (it *RowIterator) fetch() {
+	if it.table == nil {
+		it.table, it.err = it.base.GetTableCtx(it.ctx, it.tableName)
+		if it.err != nil {
+			return
+		}
+	}
+
+	limit := it.pageSize
+	if it.remaining >= 0 && it.remaining < limit {
+		limit = it.remaining
+	}
+	if limit == 0 {
+		it.done = true
+		return
+	}
+
+	opts := it.opts
+	opts.Limit = limit
+	rows, err := it.base.ListRowsWithOptionsCtx(it.ctx, it.tableName, it.viewName, &opts)
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.page, it.err = DecodeRows(it.table, rows)
+	if it.err != nil {
+		return
+	}
+
+	it.opts.Start += len(it.page)
+	if it.remaining >= 0 {
+		it.remaining -= len(it.page)
+	}
+	if len(it.page) < limit {
+		it.done = true
+	}
+}
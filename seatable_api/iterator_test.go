@@ -0,0 +1,75 @@
+package seatable_api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestRowIterator(t *testing.T) {
+	const total = 2500
+
+	si := newStandIn(t)
+	si.handle("/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMetadata))
+	})
+	requests := 0
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		start, _ := strconv.Atoi(q.Get("start"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		if q.Get("order_by") != "age" || q.Get("direction") != "desc" {
+			t.Errorf("order options not sent: %v", q)
+		}
+
+		if limit == 0 || limit > defaultPageSize {
+			limit = defaultPageSize
+		}
+		rows := []interface{}{}
+		for i := start; i < start+limit && i < total; i++ {
+			rows = append(rows, map[string]interface{}{"_id": fmt.Sprintf("row%d", i), "age": i})
+		}
+		writeJSON(w, map[string]interface{}{"rows": rows})
+	})
+	b := si.base(t)
+
+	opts := &ListRowsOptions{OrderBy: "age", Direction: "desc"}
+	it := b.IterateRows(tableName, "", opts)
+	n := 0
+	for it.Next() {
+		age, err := it.Row().Float("age")
+		if err != nil || int(age) != n {
+			t.Fatalf("row %d: age = %v, %v", n, age, err)
+		}
+		n++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+	if n != total || requests != 3 {
+		t.Errorf("got %d rows in %d requests, want %d in 3", n, requests, total)
+	}
+
+	// Larger pages than the server sends would end the iteration early.
+	requests = 0
+	it = b.IterateRows(tableName, "", &ListRowsOptions{OrderBy: "age", Direction: "desc"}).PageSize(5000)
+	n = 0
+	for it.Next() {
+		n++
+	}
+	if it.Err() != nil || n != total || requests != 3 {
+		t.Errorf("got %d rows in %d requests with a large page size, %v", n, requests, it.Err())
+	}
+
+	opts = &ListRowsOptions{Start: 10, Limit: 15, OrderBy: "age", Direction: "desc"}
+	it = b.IterateRows(tableName, "", opts).PageSize(10)
+	n = 0
+	for it.Next() {
+		n++
+	}
+	if it.Err() != nil || n != 15 || it.Row() != nil {
+		t.Errorf("limited iteration returned %d rows, %v", n, it.Err())
+	}
+}
@@ -0,0 +1,233 @@
+package seatable_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// linkCache holds the link ids of link columns. A nil cache caches
+// nothing.
+type linkCache struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+func newLinkCache() *linkCache {
+	return &linkCache{ids: make(map[string]string)}
+}
+
+func (c *linkCache) get(tableName, columnName string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.ids[tableName+"\x00"+columnName]
+	return id, ok
+}
+
+func (c *linkCache) set(tableName, columnName, linkID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[tableName+"\x00"+columnName] = linkID
+}
+
+// reset drops all cached link ids. Renaming, retyping or deleting a
+// column or table resets the whole cache, as a link column has a
+// counterpart in the other table of the link.
+func (c *linkCache) reset() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids = make(map[string]string)
+}
+
+// ResetLinkCache drops the link ids cached by GetColumnLinkID for the
+// Base and its clones. Schema changes made through the Base reset the
+// cache; call ResetLinkCache after link columns were changed elsewhere.
+func (s *Base) ResetLinkCache() {
+	s.links.reset()
+}
+
+// BatchUpdateLinks sets the linked rows of several rows at once. links
+// maps a row id of tableName to the ids of the rows in otherTableName it
+// links to, replacing its current links; an empty list removes all of
+// them. Rows are sent in requests of at most 1000 rows.
+func (s *Base) BatchUpdateLinks(linkID, tableName, otherTableName string, links map[string][]string) (map[string]interface{}, error) {
+	return s.BatchUpdateLinksCtx(context.Background(), linkID, tableName, otherTableName, links)
+}
+
+func (s *Base) BatchUpdateLinksCtx(ctx context.Context, linkID, tableName, otherTableName string, links map[string][]string) (map[string]interface{}, error) {
+	rowIDs := make([]string, 0, len(links))
+	for id := range links {
+		rowIDs = append(rowIDs, id)
+	}
+	sort.Strings(rowIDs)
+
+	ret := runBatch(ctx, len(rowIDs), nil, func(ctx context.Context, start, end int) (map[string]interface{}, error) {
+		otherRowIDs := make(map[string][]string, end-start)
+		for _, id := range rowIDs[start:end] {
+			otherRowIDs[id] = links[id]
+			if otherRowIDs[id] == nil {
+				otherRowIDs[id] = []string{}
+			}
+		}
+		return s.batchUpdateLinks(ctx, linkID, tableName, otherTableName, rowIDs[start:end], otherRowIDs)
+	})
+	if err := ret.Err(); err != nil {
+		return nil, err
+	}
+	return ret.Response(), nil
+}
+
+func (s *Base) batchUpdateLinks(ctx context.Context, linkID, tableName, otherTableName string, rowIDs []string, otherRowIDs map[string][]string) (map[string]interface{}, error) {
+	url := s.dtableURL("/batch-update-links/")
+
+	data := make(map[string]interface{})
+	data["link_id"] = linkID
+	data["table_name"] = tableName
+	data["other_table_name"] = otherTableName
+	data["row_id_list"] = rowIDs
+	data["other_rows_ids_map"] = otherRowIDs
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		err := fmt.Errorf("failed to encode put data: %v", err)
+		return nil, err
+	}
+
+	status, body, err := s.httpPut(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to post links to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("PUT", url, status, body)
+		return nil, err
+	}
+
+	rsp, err := parseResponse(body)
+	if err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+
+	ret, ok := rsp.(map[string]interface{})
+	if !ok {
+		err := fmt.Errorf("failed to assert response")
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// linkedRowsPageSize is the number of linked rows requested per row and
+// request.
+const linkedRowsPageSize = 1000
+
+// GetLinkedRows returns the rows linked to each of rowIDs through the
+// link column columnName, keyed by row id. It uses dtable-db.
+func (s *Base) GetLinkedRows(tableName, columnName string, rowIDs []string) (map[string][]*LinkValue, error) {
+	return s.GetLinkedRowsCtx(context.Background(), tableName, columnName, rowIDs)
+}
+
+func (s *Base) GetLinkedRowsCtx(ctx context.Context, tableName, columnName string, rowIDs []string) (map[string][]*LinkValue, error) {
+	table, err := s.GetTableCtx(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	col := table.ColumnByName(columnName)
+	if col == nil {
+		col = table.ColumnByKey(columnName)
+	}
+	if col == nil || col.Type != LINK {
+		err := fmt.Errorf("link column %s in table %s: %w", columnName, tableName, ErrNotFound)
+		return nil, err
+	}
+
+	// Rows with more links than a page are asked for again with the next
+	// offset, at most maxBatchRows rows at a time.
+	ret := make(map[string][]*LinkValue, len(rowIDs))
+	offsets := make(map[string]int, len(rowIDs))
+	pending := rowIDs
+	for len(pending) > 0 {
+		chunk := pending
+		if len(chunk) > maxBatchRows {
+			chunk = chunk[:maxBatchRows]
+		}
+		page, err := s.linkedRecords(ctx, table.ID, col.Key, chunk, offsets)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []string
+		for _, id := range chunk {
+			links := page[id]
+			ret[id] = append(ret[id], links...)
+			if len(links) == linkedRowsPageSize {
+				offsets[id] += len(links)
+				next = append(next, id)
+			}
+		}
+		pending = append(next, pending[len(chunk):]...)
+	}
+	return ret, nil
+}
+
+func (s *Base) linkedRecords(ctx context.Context, tableID, columnKey string, rowIDs []string, offsets map[string]int) (map[string][]*LinkValue, error) {
+	dbURL := s.dtableDbURL()
+	if dbURL == "" {
+		err := fmt.Errorf("dtable-db url unknown, the server may not support linked records")
+		return nil, err
+	}
+	url := dbURL + "/api/v1/linked-records/" + s.dtableUUID()
+
+	rows := make([]map[string]interface{}, 0, len(rowIDs))
+	for _, id := range rowIDs {
+		rows = append(rows, map[string]interface{}{
+			"row_id": id,
+			"offset": offsets[id],
+			"limit":  linkedRowsPageSize,
+		})
+	}
+
+	data := make(map[string]interface{})
+	data["table_id"] = tableID
+	data["link_column"] = columnKey
+	data["rows"] = rows
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		err := fmt.Errorf("failed to encode post data: %v", err)
+		return nil, err
+	}
+
+	status, body, err := s.httpPost(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to post linked records to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("POST", url, status, body)
+		return nil, err
+	}
+
+	ret := make(map[string][]*LinkValue)
+	if err := json.Unmarshal(body, &ret); err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+
+	return ret, nil
+}
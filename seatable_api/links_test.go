@@ -0,0 +1,191 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetColumnLinkID(t *testing.T) {
+	table := testTable(t, "table1")
+	requests := 0
+
+	si := newStandIn(t)
+	si.handle("/columns/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeJSON(w, map[string]interface{}{"columns": table.Columns})
+	})
+	b := si.base(t)
+
+	for i := 0; i < 2; i++ {
+		linkID, err := b.GetColumnLinkID("table1", "Projects", "")
+		if err != nil || linkID != "aB3d" {
+			t.Fatalf("link id = %q, %v", linkID, err)
+		}
+	}
+	clone, _ := b.Clone()
+	if linkID, err := clone.GetColumnLinkID("table1", "Projects", ""); err != nil || linkID != "aB3d" {
+		t.Fatalf("link id of clone = %q, %v", linkID, err)
+	}
+	if requests != 1 {
+		t.Errorf("expected one request, got %d", requests)
+	}
+
+	if _, err := b.GetColumnLinkID("table1", "Name", ""); !IsNotFound(err) {
+		t.Errorf("expected not found for a text column, got %v", err)
+	}
+}
+
+func TestGetColumnLinkIDAfterRename(t *testing.T) {
+	table := testTable(t, "table1")
+	requests := 0
+
+	si := newStandIn(t)
+	si.handle("/columns/", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		switch r.Method {
+		case http.MethodGet:
+			requests++
+		case http.MethodPut:
+			table.ColumnByKey(req["column_key"].(string)).Name = req["new_column_name"].(string)
+		case http.MethodPost:
+			table.Columns = append(table.Columns, &Column{
+				Key: "w1x2", Name: req["column_name"].(string), Type: LINK,
+				Data: &ColumnData{LinkID: "zZ9y", TableID: "0000", OtherTableID: "9Xyz"},
+			})
+		}
+		writeJSON(w, map[string]interface{}{"columns": table.Columns})
+	})
+	b := si.base(t)
+	clone, _ := b.Clone()
+
+	if linkID, err := b.GetColumnLinkID("table1", "Projects", ""); err != nil || linkID != "aB3d" {
+		t.Fatalf("link id = %q, %v", linkID, err)
+	}
+
+	// Rename the link column and create a new one under its old name.
+	if _, err := b.RenameColumn("table1", "q7r8", "Old projects"); err != nil {
+		t.Fatalf("failed to rename column: %v", err)
+	}
+	if _, err := b.InsertColumn("table1", "Projects", LINK, ""); err != nil {
+		t.Fatalf("failed to insert column: %v", err)
+	}
+
+	if linkID, err := clone.GetColumnLinkID("table1", "Projects", ""); err != nil || linkID != "zZ9y" {
+		t.Errorf("link id after rename = %q, %v", linkID, err)
+	}
+	if linkID, err := b.GetColumnLinkID("table1", "Old projects", ""); err != nil || linkID != "aB3d" {
+		t.Errorf("link id of renamed column = %q, %v", linkID, err)
+	}
+
+	// Changes made by other clients need an explicit reset.
+	table.ColumnByKey("w1x2").Data.LinkID = "cD4e"
+	b.ResetLinkCache()
+	if linkID, err := b.GetColumnLinkID("table1", "Projects", ""); err != nil || linkID != "cD4e" {
+		t.Errorf("link id after reset = %q, %v", linkID, err)
+	}
+	if requests != 4 {
+		t.Errorf("expected 4 requests, got %d", requests)
+	}
+}
+
+func TestBatchUpdateLinks(t *testing.T) {
+	var req map[string]interface{}
+	si := newStandIn(t)
+	si.handle("/batch-update-links/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		writeJSON(w, map[string]interface{}{"success": true})
+	})
+	b := si.base(t)
+
+	_, err := b.BatchUpdateLinks("aB3d", "table1", "Projects", map[string][]string{
+		"r2": {"p1", "p2"},
+		"r1": nil,
+	})
+	if err != nil {
+		t.Fatalf("failed to update links: %v", err)
+	}
+	want := map[string]interface{}{
+		"link_id":          "aB3d",
+		"table_name":       "table1",
+		"other_table_name": "Projects",
+		"row_id_list":      []interface{}{"r1", "r2"},
+		"other_rows_ids_map": map[string]interface{}{
+			"r1": []interface{}{},
+			"r2": []interface{}{"p1", "p2"},
+		},
+	}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("got %v\nwant %v", req, want)
+	}
+}
+
+func TestGetLinkedRows(t *testing.T) {
+	const linked = 1500
+	var requests, maxRows int
+	si := newStandIn(t)
+	si.handle("/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMetadata))
+	})
+	si.handle("/dtable-db/api/v1/linked-records/"+standInUUID, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			TableID    string `json:"table_id"`
+			LinkColumn string `json:"link_column"`
+			Rows       []struct {
+				RowID  string `json:"row_id"`
+				Offset int    `json:"offset"`
+				Limit  int    `json:"limit"`
+			} `json:"rows"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.TableID != "0000" || req.LinkColumn != "q7r8" {
+			t.Errorf("unexpected request %+v", req)
+		}
+		requests++
+		if len(req.Rows) > maxRows {
+			maxRows = len(req.Rows)
+		}
+
+		ret := make(map[string]interface{})
+		for _, row := range req.Rows {
+			links := []interface{}{}
+			if row.RowID == "r1" {
+				for i := row.Offset; i < linked && i < row.Offset+row.Limit; i++ {
+					links = append(links, map[string]interface{}{"row_id": fmt.Sprintf("p%d", i), "display_value": "Launch"})
+				}
+			}
+			ret[row.RowID] = links
+		}
+		writeJSON(w, ret)
+	})
+	b := si.base(t)
+
+	ret, err := b.GetLinkedRows("table1", "Projects", []string{"r1", "r2"})
+	if err != nil {
+		t.Fatalf("failed to get linked rows: %v", err)
+	}
+	if len(ret["r1"]) != linked || ret["r1"][linked-1].RowID != "p1499" || len(ret["r2"]) != 0 {
+		t.Errorf("got %d and %d linked rows", len(ret["r1"]), len(ret["r2"]))
+	}
+
+	// More rows than fit in one request are split up.
+	rowIDs := []string{"r1"}
+	for i := 0; i < 2500; i++ {
+		rowIDs = append(rowIDs, fmt.Sprintf("x%d", i))
+	}
+	requests, maxRows = 0, 0
+	ret, err = b.GetLinkedRows("table1", "Projects", rowIDs)
+	if err != nil {
+		t.Fatalf("failed to get linked rows: %v", err)
+	}
+	if len(ret) != len(rowIDs) || len(ret["r1"]) != linked || maxRows > maxBatchRows || requests != 3 {
+		t.Errorf("got %d rows in %d requests of up to %d rows", len(ret), requests, maxRows)
+	}
+
+	if _, err := b.GetLinkedRows("table1", "Name", []string{"r1"}); !IsNotFound(err) {
+		t.Errorf("expected not found for a text column, got %v", err)
+	}
+}
@@ -0,0 +1,364 @@
+package seatable_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Marshal and Unmarshal map struct fields to columns using the
+// "seatable" struct tag:
+//
+//	type Customer struct {
+//		ID       string     `seatable:"_id"`
+//		Name     string     `seatable:"Name"`
+//		Age      *int       `seatable:"age,omitempty"`
+//		Birthday time.Time  `seatable:"Birthday,date"`
+//		Tags     []string   `seatable:"Tags"`
+//		Notes    string     `seatable:"-"`
+//	}
+//
+// Untagged exported fields use the field name as column name. Columns
+// starting with an underscore, such as _id and _mtime, are only read.
+// Nil pointers are written as empty cells. A time.Time is written in UTC
+// to the minute, the resolution of date columns, and read back in UTC;
+// the "date" option writes it without its time of day. "readonly" marks
+// computed columns such as formulas and links that are never written.
+
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02 15:04"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	linkType = reflect.TypeOf(LinkValue{})
+)
+
+type fieldInfo struct {
+	index     int
+	column    string
+	omitEmpty bool
+	dateOnly  bool
+	readOnly  bool
+}
+
+func structFields(t reflect.Type) []fieldInfo {
+	var fields []fieldInfo
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tag := f.Tag.Get("seatable")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		info := fieldInfo{index: i, column: parts[0]}
+		if info.column == "" {
+			info.column = f.Name
+		}
+		for _, opt := range parts[1:] {
+			switch opt {
+			case "omitempty":
+				info.omitEmpty = true
+			case "date":
+				info.dateOnly = true
+			case "readonly":
+				info.readOnly = true
+			}
+		}
+		fields = append(fields, info)
+	}
+	return fields
+}
+
+func structValue(v interface{}) (reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, false
+		}
+		rv = rv.Elem()
+	}
+	return rv, rv.Kind() == reflect.Struct && rv.Type() != timeType
+}
+
+// Marshal converts a tagged struct into row data for AppendRow, UpdateRow
+// and the batch methods.
+func Marshal(v interface{}) (map[string]interface{}, error) {
+	rv, ok := structValue(v)
+	if !ok {
+		err := fmt.Errorf("can not marshal %T as row, expect a struct", v)
+		return nil, err
+	}
+
+	row := make(map[string]interface{})
+	for _, f := range structFields(rv.Type()) {
+		if f.readOnly || strings.HasPrefix(f.column, "_") {
+			continue
+		}
+		fv := rv.Field(f.index)
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		row[f.column] = marshalValue(fv, f.dateOnly)
+	}
+	return row, nil
+}
+
+func marshalValue(v reflect.Value, dateOnly bool) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return nil
+		}
+		if dateOnly {
+			return t.Format(dateLayout)
+		}
+		return t.UTC().Format(dateTimeLayout)
+	}
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// toRowData converts structs passed as row data with Marshal and leaves
+// other values unchanged.
+func toRowData(v interface{}) (interface{}, error) {
+	if _, ok := structValue(v); !ok {
+		return v, nil
+	}
+	return Marshal(v)
+}
+
+// Unmarshal stores the cells of row in the tagged struct pointed to by v.
+func Unmarshal(row map[string]interface{}, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		err := fmt.Errorf("can not unmarshal row into %T, expect a pointer to struct", v)
+		return err
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		err := fmt.Errorf("can not unmarshal row into %T, expect a pointer to struct", v)
+		return err
+	}
+
+	for _, f := range structFields(rv.Type()) {
+		value, ok := row[f.column]
+		if !ok {
+			continue
+		}
+		if err := unmarshalValue(value, rv.Field(f.index)); err != nil {
+			err := fmt.Errorf("column %s: %v", f.column, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// UnmarshalRows stores rows as returned by ListRows or FilterRows in the
+// slice pointed to by dst, whose elements are structs or struct pointers.
+func UnmarshalRows(rows interface{}, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		err := fmt.Errorf("can not unmarshal rows into %T, expect a pointer to slice", dst)
+		return err
+	}
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+
+	var list []interface{}
+	if rows != nil {
+		var ok bool
+		list, ok = rows.([]interface{})
+		if !ok {
+			err := fmt.Errorf("failed to assert rows")
+			return err
+		}
+	}
+
+	ret := reflect.MakeSlice(slice.Type(), 0, len(list))
+	for i, item := range list {
+		row, ok := item.(map[string]interface{})
+		if !ok {
+			err := fmt.Errorf("failed to assert row")
+			return err
+		}
+
+		elem := reflect.New(elemType)
+		target := elem
+		if elemType.Kind() == reflect.Ptr {
+			elem.Elem().Set(reflect.New(elemType.Elem()))
+			target = elem.Elem()
+		}
+		if err := Unmarshal(row, target.Interface()); err != nil {
+			err := fmt.Errorf("row %d: %v", i, err)
+			return err
+		}
+		ret = reflect.Append(ret, elem.Elem())
+	}
+	slice.Set(ret)
+	return nil
+}
+
+func unmarshalValue(value interface{}, dst reflect.Value) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		elem := reflect.New(dst.Type().Elem())
+		if err := unmarshalValue(value, elem.Elem()); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+
+	if dst.Type() == timeType {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("can not convert %T to time", value)
+		}
+		if s == "" {
+			dst.Set(reflect.Zero(timeType))
+			return nil
+		}
+		t, err := parseTime(s)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		rv := reflect.ValueOf(value)
+		if !rv.Type().AssignableTo(dst.Type()) {
+			return fmt.Errorf("can not convert %T to %v", value, dst.Type())
+		}
+		dst.Set(rv)
+		return nil
+	case reflect.String:
+		switch s := value.(type) {
+		case string:
+			dst.SetString(s)
+		case float64:
+			dst.SetString(strconv.FormatFloat(s, 'f', -1, 64))
+		case bool:
+			dst.SetString(strconv.FormatBool(s))
+		default:
+			return fmt.Errorf("can not convert %T to string", value)
+		}
+		return nil
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("can not convert %T to bool", value)
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		f, err := toFloat(value)
+		if err != nil {
+			return err
+		}
+		switch dst.Kind() {
+		case reflect.Float32, reflect.Float64:
+			dst.SetFloat(f)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			dst.SetUint(uint64(f))
+		default:
+			dst.SetInt(int64(f))
+		}
+		return nil
+	case reflect.Slice:
+		// A single collaborator, such as _creator, fills a []string.
+		if s, ok := value.(string); ok && dst.Type().Elem().Kind() == reflect.String {
+			list := reflect.MakeSlice(dst.Type(), 1, 1)
+			list.Index(0).SetString(s)
+			dst.Set(list)
+			return nil
+		}
+		// Links are listed as row ids unless ConvertLinkID is set.
+		elem := dst.Type().Elem()
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if list, ok := value.([]interface{}); ok && elem == linkType {
+			links := make([]interface{}, len(list))
+			for i, item := range list {
+				links[i] = item
+				if id, ok := item.(string); ok {
+					links[i] = &LinkValue{RowID: id}
+				}
+			}
+			value = links
+		}
+	}
+
+	// Files, links, geolocations and other structured cells.
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst.Addr().Interface())
+}
+
+func toFloat(value interface{}) (float64, error) {
+	switch n := value.(type) {
+	case float64:
+		return n, nil
+	case json.Number:
+		return n.Float64()
+	case string:
+		return strconv.ParseFloat(n, 64)
+	}
+	return 0, fmt.Errorf("can not convert %T to number", value)
+}
+
+// ListRowsInto lists the rows of a table and stores them in dst, a
+// pointer to a slice of tagged structs.
+func (s *Base) ListRowsInto(tableName, viewName string, dst interface{}) error {
+	return s.ListRowsIntoCtx(context.Background(), tableName, viewName, dst)
+}
+
+func (s *Base) ListRowsIntoCtx(ctx context.Context, tableName, viewName string, dst interface{}) error {
+	rows, err := s.ListRowsCtx(ctx, tableName, viewName)
+	if err != nil {
+		return err
+	}
+	return UnmarshalRows(rows, dst)
+}
+
+func (s *Base) FilterRowsInto(tableName string, filters []map[string]interface{}, viewName string, filterConjunction string, dst interface{}) error {
+	return s.FilterRowsIntoCtx(context.Background(), tableName, filters, viewName, filterConjunction, dst)
+}
+
+func (s *Base) FilterRowsIntoCtx(ctx context.Context, tableName string, filters []map[string]interface{}, viewName string, filterConjunction string, dst interface{}) error {
+	rows, err := s.FilterRowsCtx(ctx, tableName, filters, viewName, filterConjunction)
+	if err != nil {
+		return err
+	}
+	return UnmarshalRows(rows, dst)
+}
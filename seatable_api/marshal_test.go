@@ -0,0 +1,140 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type testCustomer struct {
+	ID          string       `seatable:"_id"`
+	Name        string       `seatable:"Name"`
+	Age         *int         `seatable:"age"`
+	Birthday    time.Time    `seatable:"Birthday,date"`
+	Tags        []string     `seatable:"Tags"`
+	Done        bool         `seatable:"Done"`
+	Attachments []*FileValue `seatable:"Attachments"`
+	Place       *GeolocationValue
+	Ignored     string `seatable:"-"`
+}
+
+func TestMarshal(t *testing.T) {
+	age := 20
+	c := &testCustomer{
+		ID:       "ignored",
+		Name:     "name1",
+		Age:      &age,
+		Birthday: time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC),
+		Tags:     []string{"red"},
+		Ignored:  "x",
+	}
+
+	row, err := Marshal(c)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if _, ok := row["_id"]; ok {
+		t.Errorf("_id must not be written")
+	}
+	if _, ok := row["Ignored"]; ok {
+		t.Errorf("field tagged - must not be written")
+	}
+	if row["age"] != 20 || row["Birthday"] != "2021-03-05" || row["Place"] != nil {
+		t.Errorf("unexpected row data: %v", row)
+	}
+}
+
+func TestMarshalTimeRoundTrip(t *testing.T) {
+	type event struct {
+		Start time.Time `seatable:"Start"`
+		Day   time.Time `seatable:"Day,date"`
+	}
+	cet := time.FixedZone("CET", 3600)
+	in := event{
+		Start: time.Date(2021, 3, 5, 0, 30, 45, 0, cet),
+		Day:   time.Date(2021, 3, 5, 0, 30, 0, 0, cet),
+	}
+
+	row, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if row["Start"] != "2021-03-04 23:30" || row["Day"] != "2021-03-05" {
+		t.Errorf("unexpected row data: %v", row)
+	}
+
+	var out event
+	if err := Unmarshal(row, &out); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if !out.Start.Equal(in.Start.Truncate(time.Minute)) {
+		t.Errorf("time changed on round trip: %v, want %v", out.Start, in.Start)
+	}
+}
+
+func TestUnmarshalRows(t *testing.T) {
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(testRow), &values); err != nil {
+		t.Fatal(err)
+	}
+	values["Tags"] = []interface{}{"red", "blue"}
+	values["Place"] = nil
+
+	var customers []*testCustomer
+	if err := UnmarshalRows([]interface{}{values}, &customers); err != nil {
+		t.Fatalf("failed to unmarshal rows: %v", err)
+	}
+	if len(customers) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(customers))
+	}
+
+	c := customers[0]
+	if c.ID != "Qtf7xPmoRaiFyQPO1aENTjb" || c.Name != "name1" || c.Age == nil || *c.Age != 20 {
+		t.Errorf("unexpected customer: %+v", c)
+	}
+	if c.Birthday.Day() != 5 || len(c.Tags) != 2 || !c.Done {
+		t.Errorf("unexpected customer: %+v", c)
+	}
+	if len(c.Attachments) != 1 || c.Attachments[0].Name != "a.md" || c.Place != nil {
+		t.Errorf("unexpected customer: %+v", c)
+	}
+}
+
+func TestUnmarshalLinkIDs(t *testing.T) {
+	var row struct {
+		Projects []*LinkValue `seatable:"Projects,readonly"`
+		Owners   []LinkValue  `seatable:"Owners,readonly"`
+	}
+	values := map[string]interface{}{
+		"Projects": []interface{}{"rowA", map[string]interface{}{"row_id": "rowB", "display_value": "Launch"}},
+		"Owners":   []interface{}{"rowC"},
+	}
+	if err := Unmarshal(values, &row); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if len(row.Projects) != 2 || row.Projects[0].RowID != "rowA" || row.Projects[1].DisplayValue != "Launch" {
+		t.Errorf("unexpected links %+v", row.Projects)
+	}
+	if len(row.Owners) != 1 || row.Owners[0].RowID != "rowC" {
+		t.Errorf("unexpected links %+v", row.Owners)
+	}
+}
+
+func TestAppendRowStruct(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		var data map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&data)
+		writeJSON(w, data["row"])
+	})
+	b := si.base(t)
+
+	ret, err := b.AppendRow(tableName, testCustomer{Name: "name1"})
+	if err != nil {
+		t.Fatalf("failed to append row: %v", err)
+	}
+	if ret["Name"] != "name1" {
+		t.Errorf("struct row not sent with column names: %v", ret)
+	}
+}
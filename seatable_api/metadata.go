@@ -0,0 +1,156 @@
+package seatable_api
+
+// Metadata describes the tables, columns and views of a base as returned
+// by GetMetadata.
+type Metadata struct {
+	Tables        []*Table `json:"tables"`
+	Version       int64    `json:"version"`
+	FormatVersion int64    `json:"format_version"`
+}
+
+type Table struct {
+	ID             string    `json:"_id"`
+	Name           string    `json:"name"`
+	IsHeaderLocked bool      `json:"is_header_locked"`
+	Columns        []*Column `json:"columns"`
+	Views          []*View   `json:"views"`
+}
+
+type Column struct {
+	Key       string      `json:"key"`
+	Name      string      `json:"name"`
+	Type      ColumnTypes `json:"type"`
+	Width     int         `json:"width"`
+	Editable  bool        `json:"editable"`
+	Resizable bool        `json:"resizable"`
+	Frozen    bool        `json:"frozen"`
+	Data      *ColumnData `json:"data"`
+}
+
+// ColumnData holds the type specific settings of a column. Only the
+// fields that apply to the column's type are set.
+type ColumnData struct {
+	// single-select and multiple-select
+	Options []*SelectOption `json:"options,omitempty"`
+
+	// number, date, duration and auto-number
+	Format          string `json:"format,omitempty"`
+	Decimal         string `json:"decimal,omitempty"`
+	Thousands       string `json:"thousands,omitempty"`
+	Precision       int    `json:"precision,omitempty"`
+	EnablePrecision bool   `json:"enable_precision,omitempty"`
+	DurationFormat  string `json:"duration_format,omitempty"`
+
+	// link
+	LinkID           string `json:"link_id,omitempty"`
+	TableID          string `json:"table_id,omitempty"`
+	OtherTableID     string `json:"other_table_id,omitempty"`
+	IsInternalLink   bool   `json:"is_internal_link,omitempty"`
+	DisplayColumnKey string `json:"display_column_key,omitempty"`
+
+	// formula and link-formula
+	Formula         string   `json:"formula,omitempty"`
+	ResultType      string   `json:"result_type,omitempty"`
+	OperatedColumns []string `json:"operated_columns,omitempty"`
+	ArrayType       string   `json:"array_type,omitempty"`
+	LinkColumnKey   string   `json:"link_column_key,omitempty"`
+
+	// rating
+	RatingMaxNumber int    `json:"rating_max_number,omitempty"`
+	RatingStyle     string `json:"rating_style,omitempty"`
+
+	// geolocation
+	GeoFormat string `json:"geo_format,omitempty"`
+
+	// button
+	ButtonName string `json:"button_name,omitempty"`
+	ButtonType string `json:"button_type,omitempty"`
+}
+
+type SelectOption struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Color     string `json:"color,omitempty"`
+	TextColor string `json:"textColor,omitempty"`
+}
+
+type View struct {
+	ID                string                   `json:"_id"`
+	Name              string                   `json:"name"`
+	Type              string                   `json:"type"`
+	IsLocked          bool                     `json:"is_locked"`
+	FilterConjunction string                   `json:"filter_conjunction"`
+	Filters           []map[string]interface{} `json:"filters"`
+	Sorts             []map[string]interface{} `json:"sorts"`
+	Groupbys          []map[string]interface{} `json:"groupbys"`
+	HiddenColumns     []string                 `json:"hidden_columns"`
+}
+
+func (m *Metadata) TableByName(name string) *Table {
+	for _, t := range m.Tables {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func (m *Metadata) TableByID(id string) *Table {
+	for _, t := range m.Tables {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (t *Table) ColumnByName(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (t *Table) ColumnByKey(key string) *Column {
+	for _, c := range t.Columns {
+		if c.Key == key {
+			return c
+		}
+	}
+	return nil
+}
+
+func (t *Table) ViewByName(name string) *View {
+	for _, v := range t.Views {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+func (c *Column) OptionByName(name string) *SelectOption {
+	if c.Data == nil {
+		return nil
+	}
+	for _, o := range c.Data.Options {
+		if o.Name == name {
+			return o
+		}
+	}
+	return nil
+}
+
+func (c *Column) OptionByID(id string) *SelectOption {
+	if c.Data == nil {
+		return nil
+	}
+	for _, o := range c.Data.Options {
+		if o.ID == id {
+			return o
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package seatable_api
+
+import (
+	"net/http"
+	"testing"
+)
+
+const testMetadata = `{"metadata": {
+	"version": 12, "format_version": 8,
+	"tables": [{
+		"_id": "0000", "name": "table1",
+		"columns": [
+			{"key": "0000", "name": "Name", "type": "text", "width": 200, "editable": true},
+			{"key": "a1b2", "name": "age", "type": "number", "data": {"format": "number", "decimal": "dot", "thousands": "no"}},
+			{"key": "c3d4", "name": "Birthday", "type": "date", "data": {"format": "YYYY-MM-DD"}},
+			{"key": "e5f6", "name": "Status", "type": "single-select", "data": {"options": [
+				{"id": "111111", "name": "Open", "color": "#FFFCB5", "textColor": "#202428"},
+				{"id": "222222", "name": "Done", "color": "#B7CEF9", "textColor": "#202428"}
+			]}},
+			{"key": "g7h8", "name": "Tags", "type": "multiple-select", "data": {"options": [
+				{"id": "333333", "name": "red"}, {"id": "444444", "name": "blue"}
+			]}},
+			{"key": "i9j0", "name": "Done", "type": "checkbox"},
+			{"key": "k1l2", "name": "Owner", "type": "collaborator"},
+			{"key": "m3n4", "name": "Attachments", "type": "file"},
+			{"key": "o5p6", "name": "Photos", "type": "image"},
+			{"key": "q7r8", "name": "Projects", "type": "link", "data": {"link_id": "aB3d", "table_id": "0000", "other_table_id": "9Xyz", "is_internal_link": true, "display_column_key": "0000"}},
+			{"key": "s9t0", "name": "Place", "type": "geolocation", "data": {"geo_format": "lng_lat"}},
+			{"key": "u1v2", "name": "Score", "type": "formula", "data": {"formula": "{age} * 2", "result_type": "number"}}
+		],
+		"views": [{"_id": "0000", "name": "Default View", "type": "table", "filter_conjunction": "And", "filters": [], "sorts": [], "groupbys": [], "hidden_columns": []}]
+	}, {
+		"_id": "9Xyz", "name": "Projects",
+		"columns": [{"key": "0000", "name": "Title", "type": "text"}],
+		"views": [{"_id": "0000", "name": "Default View", "type": "table"}]
+	}]
+}}`
+
+func TestGetMetadataStandIn(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMetadata))
+	})
+	b := si.base(t)
+
+	md, err := b.GetMetadata()
+	if err != nil {
+		t.Fatalf("failed to get metadata: %v", err)
+	}
+
+	table := md.TableByName("table1")
+	if table == nil {
+		t.Fatalf("table1 not found")
+	}
+	if md.TableByID("9Xyz") == nil || md.TableByName("missing") != nil {
+		t.Errorf("table lookup by id or missing name failed")
+	}
+
+	status := table.ColumnByName("Status")
+	if status == nil || status.Type != "single-select" {
+		t.Fatalf("Status column not decoded: %+v", status)
+	}
+	if o := status.OptionByName("Done"); o == nil || o.ID != "222222" {
+		t.Errorf("option Done not found: %+v", o)
+	}
+
+	link := table.ColumnByKey("q7r8")
+	if link == nil || link.Data.LinkID != "aB3d" || link.Data.OtherTableID != "9Xyz" {
+		t.Errorf("link column data not decoded: %+v", link)
+	}
+	if table.ViewByName("Default View") == nil {
+		t.Errorf("default view not found")
+	}
+}
@@ -0,0 +1,436 @@
+package seatable_api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MIGRATIONS_TABLE is the default table recording applied migrations.
+const MIGRATIONS_TABLE = "schema_migrations"
+
+// Migration is a versioned set of schema changes. Versions must be
+// unique; migrations are applied in version order and recorded in the
+// migrations table of the base, so running the same migrations against
+// several bases brings each of them to the same schema.
+type Migration struct {
+	Version     int
+	Description string
+	Steps       []MigrationStep
+}
+
+// MigrationStep is a single schema change. Applied reports whether the
+// change is already present in the metadata, in which case the step is
+// skipped; this lets an interrupted migration be run again.
+type MigrationStep interface {
+	Describe() string
+	Applied(md *Metadata) bool
+	Apply(ctx context.Context, base *Base, md *Metadata) error
+}
+
+func findColumn(md *Metadata, tableName, columnName string) *Column {
+	table := md.TableByName(tableName)
+	if table == nil {
+		return nil
+	}
+	return table.ColumnByName(columnName)
+}
+
+func requireColumn(md *Metadata, tableName, columnName string) (*Column, error) {
+	col := findColumn(md, tableName, columnName)
+	if col == nil {
+		err := fmt.Errorf("column %s in table %s: %w", columnName, tableName, ErrNotFound)
+		return nil, err
+	}
+	return col, nil
+}
+
+// AddTableStep creates a table; see Base.AddTable for Columns.
+type AddTableStep struct {
+	Name    string
+	Columns []*TableColumn
+}
+
+func (st *AddTableStep) Describe() string {
+	return fmt.Sprintf("add table %s", st.Name)
+}
+
+func (st *AddTableStep) Applied(md *Metadata) bool {
+	return md.TableByName(st.Name) != nil
+}
+
+func (st *AddTableStep) Apply(ctx context.Context, base *Base, md *Metadata) error {
+	_, err := base.AddTableCtx(ctx, st.Name, "", st.Columns)
+	return err
+}
+
+// AddColumnStep adds a column; Data is passed to Base.InsertColumnWithData.
+type AddColumnStep struct {
+	Table string
+	Name  string
+	Type  ColumnTypes
+	Data  TypedColumnData
+}
+
+func (st *AddColumnStep) Describe() string {
+	return fmt.Sprintf("add column %s (%s) to table %s", st.Name, st.Type, st.Table)
+}
+
+func (st *AddColumnStep) Applied(md *Metadata) bool {
+	return findColumn(md, st.Table, st.Name) != nil
+}
+
+func (st *AddColumnStep) Apply(ctx context.Context, base *Base, md *Metadata) error {
+	_, err := base.InsertColumnWithDataCtx(ctx, st.Table, st.Name, st.Type, "", st.Data)
+	return err
+}
+
+type ChangeColumnTypeStep struct {
+	Table  string
+	Column string
+	Type   ColumnTypes
+}
+
+func (st *ChangeColumnTypeStep) Describe() string {
+	return fmt.Sprintf("change type of column %s in table %s to %s", st.Column, st.Table, st.Type)
+}
+
+func (st *ChangeColumnTypeStep) Applied(md *Metadata) bool {
+	col := findColumn(md, st.Table, st.Column)
+	return col != nil && col.Type == st.Type
+}
+
+func (st *ChangeColumnTypeStep) Apply(ctx context.Context, base *Base, md *Metadata) error {
+	col, err := requireColumn(md, st.Table, st.Column)
+	if err != nil {
+		return err
+	}
+	_, err = base.ModifyColumnTypeCtx(ctx, st.Table, col.Key, st.Type)
+	return err
+}
+
+// AddSelectOptionsStep adds the options of a select column that do not
+// exist yet.
+type AddSelectOptionsStep struct {
+	Table   string
+	Column  string
+	Options []*SelectOption
+}
+
+func (st *AddSelectOptionsStep) Describe() string {
+	names := make([]string, 0, len(st.Options))
+	for _, o := range st.Options {
+		names = append(names, o.Name)
+	}
+	return fmt.Sprintf("add options %s to column %s in table %s", strings.Join(names, ", "), st.Column, st.Table)
+}
+
+func (st *AddSelectOptionsStep) missing(col *Column) []*SelectOption {
+	var ret []*SelectOption
+	for _, o := range st.Options {
+		if col.OptionByName(o.Name) == nil {
+			ret = append(ret, o)
+		}
+	}
+	return ret
+}
+
+func (st *AddSelectOptionsStep) Applied(md *Metadata) bool {
+	col := findColumn(md, st.Table, st.Column)
+	return col != nil && len(st.missing(col)) == 0
+}
+
+func (st *AddSelectOptionsStep) Apply(ctx context.Context, base *Base, md *Metadata) error {
+	col, err := requireColumn(md, st.Table, st.Column)
+	if err != nil {
+		return err
+	}
+	_, err = base.AddColumnOptionsCtx(ctx, st.Table, col.Key, st.missing(col))
+	return err
+}
+
+type RenameColumnStep struct {
+	Table   string
+	Column  string
+	NewName string
+}
+
+func (st *RenameColumnStep) Describe() string {
+	return fmt.Sprintf("rename column %s in table %s to %s", st.Column, st.Table, st.NewName)
+}
+
+func (st *RenameColumnStep) Applied(md *Metadata) bool {
+	return findColumn(md, st.Table, st.Column) == nil && findColumn(md, st.Table, st.NewName) != nil
+}
+
+func (st *RenameColumnStep) Apply(ctx context.Context, base *Base, md *Metadata) error {
+	col, err := requireColumn(md, st.Table, st.Column)
+	if err != nil {
+		return err
+	}
+	_, err = base.RenameColumnCtx(ctx, st.Table, col.Key, st.NewName)
+	return err
+}
+
+type DeleteColumnStep struct {
+	Table  string
+	Column string
+}
+
+func (st *DeleteColumnStep) Describe() string {
+	return fmt.Sprintf("delete column %s from table %s", st.Column, st.Table)
+}
+
+func (st *DeleteColumnStep) Applied(md *Metadata) bool {
+	return findColumn(md, st.Table, st.Column) == nil
+}
+
+func (st *DeleteColumnStep) Apply(ctx context.Context, base *Base, md *Metadata) error {
+	col, err := requireColumn(md, st.Table, st.Column)
+	if err != nil {
+		return err
+	}
+	_, err = base.DeleteColumnCtx(ctx, st.Table, col.Key)
+	return err
+}
+
+// Migrator applies migrations to a base. Migrations must not be applied
+// to the same base by several migrators at once.
+type Migrator struct {
+	Base       *Base
+	Migrations []*Migration
+	// Table records the applied migrations, MIGRATIONS_TABLE by default.
+	Table string
+	// Log receives a line for each step applied by Up.
+	Log io.Writer
+}
+
+func NewMigrator(base *Base, migrations ...*Migration) *Migrator {
+	return &Migrator{Base: base, Migrations: migrations}
+}
+
+// PlannedMigration lists the steps of a pending migration, split into
+// the steps to run and those skipped as already applied.
+type PlannedMigration struct {
+	Migration *Migration
+	Steps     []MigrationStep
+	Skipped   []MigrationStep
+}
+
+// MigrationPlan is the dry-run result of Plan.
+type MigrationPlan struct {
+	CreateTable string
+	Migrations  []*PlannedMigration
+}
+
+func (p *MigrationPlan) Empty() bool {
+	return p.CreateTable == "" && len(p.Migrations) == 0
+}
+
+func (p *MigrationPlan) String() string {
+	if p.Empty() {
+		return "no pending migrations\n"
+	}
+
+	var b strings.Builder
+	if p.CreateTable != "" {
+		fmt.Fprintf(&b, "create table %s\n", p.CreateTable)
+	}
+	for _, m := range p.Migrations {
+		fmt.Fprintf(&b, "migration %d: %s\n", m.Migration.Version, m.Migration.Description)
+		for _, st := range m.Steps {
+			fmt.Fprintf(&b, "  %s\n", st.Describe())
+		}
+		for _, st := range m.Skipped {
+			fmt.Fprintf(&b, "  skip %s, already applied\n", st.Describe())
+		}
+	}
+	return b.String()
+}
+
+func (m *Migrator) table() string {
+	if m.Table == "" {
+		return MIGRATIONS_TABLE
+	}
+	return m.Table
+}
+
+func (m *Migrator) sorted() ([]*Migration, error) {
+	list := make([]*Migration, len(m.Migrations))
+	copy(list, m.Migrations)
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+	for i := 1; i < len(list); i++ {
+		if list[i].Version == list[i-1].Version {
+			err := fmt.Errorf("duplicate migration version %d", list[i].Version)
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// AppliedVersions returns the versions recorded in the migrations table.
+func (m *Migrator) AppliedVersions() (map[int]bool, error) {
+	return m.AppliedVersionsCtx(context.Background())
+}
+
+func (m *Migrator) AppliedVersionsCtx(ctx context.Context) (map[int]bool, error) {
+	md, err := m.Base.GetMetadataCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.appliedVersions(ctx, md)
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, md *Metadata) (map[int]bool, error) {
+	ret := make(map[int]bool)
+	table := md.TableByName(m.table())
+	if table == nil {
+		return ret, nil
+	}
+
+	it := m.Base.IterateRowsCtx(ctx, table.Name, "", nil)
+	for it.Next() {
+		v, err := it.Row().Float("Version")
+		if err == ErrNoValue {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ret[int(v)] = true
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Plan returns the migrations and steps Up would apply without changing
+// the base.
+func (m *Migrator) Plan() (*MigrationPlan, error) {
+	return m.PlanCtx(context.Background())
+}
+
+func (m *Migrator) PlanCtx(ctx context.Context) (*MigrationPlan, error) {
+	migrations, err := m.sorted()
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := m.Base.GetMetadataCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.appliedVersions(ctx, md)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := new(MigrationPlan)
+	if md.TableByName(m.table()) == nil {
+		plan.CreateTable = m.table()
+	}
+	for _, mig := range migrations {
+		if applied[mig.Version] {
+			continue
+		}
+		planned := &PlannedMigration{Migration: mig}
+		for _, st := range mig.Steps {
+			if st.Applied(md) {
+				planned.Skipped = append(planned.Skipped, st)
+			} else {
+				planned.Steps = append(planned.Steps, st)
+			}
+		}
+		plan.Migrations = append(plan.Migrations, planned)
+	}
+	return plan, nil
+}
+
+// Up applies the pending migrations in version order and records each
+// of them once all its steps are applied. It returns the versions it
+// applied.
+func (m *Migrator) Up() ([]int, error) {
+	return m.UpCtx(context.Background())
+}
+
+func (m *Migrator) UpCtx(ctx context.Context) ([]int, error) {
+	migrations, err := m.sorted()
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := m.Base.GetMetadataCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if md.TableByName(m.table()) == nil {
+		m.logf("create table %s", m.table())
+		if err := m.createTable(ctx); err != nil {
+			return nil, err
+		}
+	}
+	applied, err := m.appliedVersions(ctx, md)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []int
+	for _, mig := range migrations {
+		if applied[mig.Version] {
+			continue
+		}
+
+		m.logf("migration %d: %s", mig.Version, mig.Description)
+		for i, st := range mig.Steps {
+			// Steps change the schema, so each one sees fresh metadata.
+			md, err := m.Base.GetMetadataCtx(ctx)
+			if err != nil {
+				return ret, err
+			}
+			if st.Applied(md) {
+				m.logf("  skip %s, already applied", st.Describe())
+				continue
+			}
+
+			m.logf("  %s", st.Describe())
+			if err := st.Apply(ctx, m.Base, md); err != nil {
+				err := fmt.Errorf("migration %d, step %d (%s): %w", mig.Version, i+1, st.Describe(), err)
+				return ret, err
+			}
+		}
+
+		row := map[string]interface{}{
+			"Version":     mig.Version,
+			"Description": mig.Description,
+			"Applied":     time.Now().UTC().Format(dateTimeLayout),
+		}
+		if _, err := m.Base.AppendRowCtx(ctx, m.table(), row); err != nil {
+			err := fmt.Errorf("failed to record migration %d: %w", mig.Version, err)
+			return ret, err
+		}
+		ret = append(ret, mig.Version)
+	}
+	return ret, nil
+}
+
+func (m *Migrator) createTable(ctx context.Context) error {
+	columns := []*TableColumn{
+		{Name: "Version", Type: NUMBER},
+		{Name: "Description", Type: TEXT},
+		{Name: "Applied", Type: DATE, Data: &DateColumnData{Format: DATE_FORMAT_ISO_MINUTE}},
+	}
+	_, err := m.Base.AddTableCtx(ctx, m.table(), "", columns)
+	return err
+}
+
+func (m *Migrator) logf(format string, args ...interface{}) {
+	if m.Log != nil {
+		fmt.Fprintf(m.Log, format+"\n", args...)
+	}
+}
@@ -0,0 +1,192 @@
+package seatable_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// schemaStandIn keeps the metadata and migration rows of a base in
+// memory, changed by the table, column and row endpoints.
+type schemaStandIn struct {
+	md      *Metadata
+	rows    []map[string]interface{}
+	changes int
+}
+
+func newSchemaStandIn(t *testing.T, si *standIn) *schemaStandIn {
+	ss := &schemaStandIn{md: &Metadata{Tables: []*Table{{
+		ID: "0000", Name: "table1",
+		Columns: []*Column{
+			{Key: "0000", Name: "Name", Type: TEXT},
+			{Key: "e5f6", Name: "Status", Type: SINGLE_SELECT, Data: &ColumnData{
+				Options: []*SelectOption{{ID: "111111", Name: "Open"}},
+			}},
+		},
+	}}}}
+
+	si.handle("/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"metadata": ss.md})
+	})
+	si.handle("/tables/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			TableName string `json:"table_name"`
+			Columns   []struct {
+				Name string      `json:"column_name"`
+				Type ColumnTypes `json:"column_type"`
+				Data *ColumnData `json:"column_data"`
+			} `json:"columns"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		table := &Table{ID: req.TableName, Name: req.TableName}
+		for _, c := range req.Columns {
+			table.Columns = append(table.Columns, &Column{Key: c.Name, Name: c.Name, Type: c.Type, Data: c.Data})
+		}
+		ss.md.Tables = append(ss.md.Tables, table)
+		ss.changes++
+		writeJSON(w, table)
+	})
+	si.handle("/columns/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			OpType     string      `json:"op_type"`
+			TableName  string      `json:"table_name"`
+			ColumnKey  string      `json:"column_key"`
+			ColumnName string      `json:"column_name"`
+			ColumnType ColumnTypes `json:"column_type"`
+			NewName    string      `json:"new_column_name"`
+			NewType    ColumnTypes `json:"new_column_type"`
+			ColumnData *ColumnData `json:"column_data"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		table := ss.md.TableByName(req.TableName)
+		if table == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ss.changes++
+
+		switch {
+		case r.Method == http.MethodPost:
+			table.Columns = append(table.Columns, &Column{Key: req.ColumnName, Name: req.ColumnName, Type: req.ColumnType})
+		case req.OpType == MODIFY_COLUMN_TYPE:
+			table.ColumnByKey(req.ColumnKey).Type = req.NewType
+		case req.OpType == RENAME_COLUMN:
+			table.ColumnByKey(req.ColumnKey).Name = req.NewName
+		case req.OpType == SET_COLUMN_DATA:
+			table.ColumnByKey(req.ColumnKey).Data = req.ColumnData
+		default:
+			t.Errorf("unexpected column request %+v", req)
+		}
+		writeJSON(w, map[string]interface{}{"success": true})
+	})
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("table_name") != MIGRATIONS_TABLE && r.Method == http.MethodGet {
+			t.Errorf("unexpected rows request %s", r.URL)
+		}
+		if r.Method == http.MethodPost {
+			var req struct {
+				Row map[string]interface{} `json:"row"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			ss.rows = append(ss.rows, req.Row)
+			writeJSON(w, map[string]interface{}{"_id": "r1"})
+			return
+		}
+		writeJSON(w, map[string]interface{}{"rows": ss.rows})
+	})
+	return ss
+}
+
+func TestMigrator(t *testing.T) {
+	si := newStandIn(t)
+	ss := newSchemaStandIn(t, si)
+	b := si.base(t)
+
+	migrations := []*Migration{
+		{Version: 2, Description: "status options", Steps: []MigrationStep{
+			&AddSelectOptionsStep{Table: "table1", Column: "Status", Options: []*SelectOption{{Name: "Open"}, {Name: "Done"}}},
+			&ChangeColumnTypeStep{Table: "table1", Column: "Score", Type: RATING},
+		}},
+		{Version: 1, Description: "customers", Steps: []MigrationStep{
+			&AddTableStep{Name: "Customers", Columns: []*TableColumn{{Name: "Name", Type: TEXT}}},
+			&AddColumnStep{Table: "table1", Name: "Score", Type: NUMBER},
+			&AddColumnStep{Table: "table1", Name: "Name", Type: TEXT},
+		}},
+	}
+	m := NewMigrator(b, migrations...)
+
+	plan, err := m.Plan()
+	if err != nil {
+		t.Fatalf("failed to plan: %v", err)
+	}
+	want := "create table schema_migrations\n" +
+		"migration 1: customers\n" +
+		"  add table Customers\n" +
+		"  add column Score (number) to table table1\n" +
+		"  skip add column Name (text) to table table1, already applied\n" +
+		"migration 2: status options\n" +
+		"  add options Open, Done to column Status in table table1\n" +
+		"  change type of column Score in table table1 to rating\n"
+	if plan.String() != want {
+		t.Errorf("plan =\n%s\nwant\n%s", plan, want)
+	}
+	if ss.changes != 0 {
+		t.Errorf("plan changed the base %d times", ss.changes)
+	}
+
+	var log bytes.Buffer
+	m.Log = &log
+	versions, err := m.Up()
+	if err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if len(versions) != 2 || versions[0] != 1 || versions[1] != 2 {
+		t.Errorf("applied versions %v", versions)
+	}
+	if !strings.Contains(log.String(), "migration 2: status options\n") {
+		t.Errorf("unexpected log\n%s", log.String())
+	}
+
+	table1 := ss.md.TableByName("table1")
+	if score := table1.ColumnByName("Score"); score == nil || score.Type != RATING {
+		t.Errorf("unexpected Score column %+v", score)
+	}
+	status := table1.ColumnByName("Status")
+	if len(status.Data.Options) != 2 || status.OptionByName("Open").ID != "111111" || status.OptionByName("Done") == nil {
+		t.Errorf("unexpected Status options %+v", status.Data.Options)
+	}
+	if ss.md.TableByName("Customers") == nil || ss.md.TableByName(MIGRATIONS_TABLE) == nil {
+		t.Error("tables not created")
+	}
+	if len(ss.rows) != 2 || ss.rows[1]["Version"] != float64(2) {
+		t.Errorf("unexpected migration rows %v", ss.rows)
+	}
+
+	// Running the same migrations again changes nothing.
+	changes := ss.changes
+	versions, err = m.Up()
+	if err != nil || len(versions) != 0 || ss.changes != changes {
+		t.Errorf("second run = %v, %v, %d changes", versions, err, ss.changes-changes)
+	}
+	plan, err = m.Plan()
+	if err != nil || !plan.Empty() {
+		t.Errorf("plan after migrating = %v, %v", plan, err)
+	}
+
+	// A new migration skips steps already done by hand.
+	m.Migrations = append(m.Migrations, &Migration{Version: 3, Description: "rename", Steps: []MigrationStep{
+		&RenameColumnStep{Table: "table1", Column: "Score", NewName: "Rating"},
+		&DeleteColumnStep{Table: "table1", Column: "Missing"},
+	}})
+	versions, err = m.Up()
+	if err != nil || len(versions) != 1 || table1.ColumnByName("Rating") == nil {
+		t.Errorf("third run = %v, %v", versions, err)
+	}
+
+	m.Migrations = append(m.Migrations, &Migration{Version: 3})
+	if _, err := m.Plan(); err == nil {
+		t.Error("expected an error for duplicate versions")
+	}
+}
@@ -0,0 +1,41 @@
+package seatable_api
+
+import (
+	"net/http"
+)
+
+// Option configures a Base created by Init.
+type Option func(*Base)
+
+// WithHTTPClient makes the Base send all requests through c, so that
+// connections are reused and proxies or TLS settings can be injected.
+func WithHTTPClient(c *http.Client) Option {
+	return func(s *Base) {
+		if c != nil {
+			s.httpClient = c
+		}
+	}
+}
+
+// WithTransport sets the RoundTripper used by the Base's http client. It
+// may be combined with WithHTTPClient in either order; the client passed
+// to WithHTTPClient is copied, not modified.
+func WithTransport(t http.RoundTripper) Option {
+	return func(s *Base) {
+		s.transport = t
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(s *Base) {
+		s.userAgent = ua
+	}
+}
+
+func (s *Base) client() *http.Client {
+	if s.httpClient == nil {
+		return http.DefaultClient
+	}
+	return s.httpClient
+}
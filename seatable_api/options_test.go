@@ -0,0 +1,103 @@
+package seatable_api
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// recordingTransport passes requests on to http.DefaultTransport and
+// records their paths and User-Agent headers.
+type recordingTransport struct {
+	mu     sync.Mutex
+	paths  []string
+	agents map[string]bool
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.paths = append(rt.paths, req.Method+" "+req.URL.Path)
+	if rt.agents == nil {
+		rt.agents = make(map[string]bool)
+	}
+	rt.agents[req.Header.Get("User-Agent")] = true
+	rt.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTransportUsedForAllRequests(t *testing.T) {
+	si := newStandIn(t)
+	si.handleFiles()
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"rows": []interface{}{}})
+	})
+
+	client := &http.Client{}
+	tests := []struct {
+		name string
+		opts func(rt *recordingTransport) []Option
+	}{
+		{"transport first", func(rt *recordingTransport) []Option {
+			return []Option{WithTransport(rt), WithHTTPClient(client), WithUserAgent("test-agent/1.0")}
+		}},
+		{"client first", func(rt *recordingTransport) []Option {
+			return []Option{WithHTTPClient(client), WithTransport(rt), WithUserAgent("test-agent/1.0")}
+		}},
+	}
+
+	for _, tt := range tests {
+		rt := new(recordingTransport)
+		b := si.base(t, tt.opts(rt)...)
+
+		if _, err := b.ListRows(tableName, ""); err != nil {
+			t.Fatalf("%s: failed to list rows: %v", tt.name, err)
+		}
+		if _, err := b.UploadBytesFile("hello.md", bytes.NewReader([]byte("hello world")), "", "file", false); err != nil {
+			t.Fatalf("%s: failed to upload file: %v", tt.name, err)
+		}
+		savePath := filepath.Join(t.TempDir(), "hello.md")
+		if err := b.DownloadFile(si.fileURL(), savePath); err != nil {
+			t.Fatalf("%s: failed to download file: %v", tt.name, err)
+		}
+		if data, _ := ioutil.ReadFile(savePath); string(data) != "hello world" {
+			t.Errorf("%s: downloaded %q", tt.name, data)
+		}
+
+		want := []string{
+			"GET /api/v2.1/dtable/app-access-token/",
+			"GET /dtable-server/api/v1/dtables/" + standInUUID + "/rows/",
+			"GET /api/v2.1/dtable/app-upload-link/",
+			"POST " + standInUploadPath,
+			"GET /api/v2.1/dtable/app-download-link/",
+			"GET " + standInDownloadPath,
+		}
+		if len(rt.paths) != len(want) {
+			t.Fatalf("%s: transport saw %v, want %v", tt.name, rt.paths, want)
+		}
+		for i := range want {
+			if rt.paths[i] != want[i] {
+				t.Errorf("%s: request %d was %s, want %s", tt.name, i, rt.paths[i], want[i])
+			}
+		}
+		if len(rt.agents) != 1 || !rt.agents["test-agent/1.0"] {
+			t.Errorf("%s: unexpected user agents %v", tt.name, rt.agents)
+		}
+		if b.client() == client || client.Transport != nil {
+			t.Errorf("%s: client passed to WithHTTPClient was modified", tt.name)
+		}
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	rt := new(recordingTransport)
+	client := &http.Client{Transport: rt}
+
+	si := newStandIn(t)
+	b := si.base(t, WithHTTPClient(client))
+	if b.client() != client || len(rt.paths) != 1 {
+		t.Errorf("client not used, transport saw %v", rt.paths)
+	}
+}
@@ -0,0 +1,164 @@
+package seatable_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxQueryLimit is the largest number of rows dtable-db returns for a
+// single query.
+const maxQueryLimit = 10000
+
+var limitPattern = regexp.MustCompile(`(?i)\blimit\s+\d+`)
+
+// QueryResult holds the rows of a SQL query together with the columns
+// of the result, which also covers computed columns such as aggregates.
+type QueryResult struct {
+	Columns []*Column
+	Rows    []map[string]interface{}
+}
+
+// TypedRows converts the result rows into Rows typed by the result
+// columns.
+func (r *QueryResult) TypedRows() []*Row {
+	table := &Table{Columns: r.Columns}
+	rows := make([]*Row, 0, len(r.Rows))
+	for _, row := range r.Rows {
+		rows = append(rows, NewRow(table, row))
+	}
+	return rows
+}
+
+type QueryOptions struct {
+	// ColumnKeys returns rows keyed by column key instead of column
+	// name, i.e. it disables convert_keys.
+	ColumnKeys bool
+}
+
+// Query runs a SQL query against dtable-db. Arguments are bound to the
+// ? placeholders of sql by the server:
+//
+//	base.Query("SELECT Name, age FROM table1 WHERE age > ? LIMIT 50", 18)
+//
+// Without a LIMIT clause the server returns at most 100 rows; use
+// QueryAll or QueryEach for larger results.
+func (s *Base) Query(sql string, args ...interface{}) (*QueryResult, error) {
+	return s.QueryCtx(context.Background(), sql, args...)
+}
+
+func (s *Base) QueryCtx(ctx context.Context, sql string, args ...interface{}) (*QueryResult, error) {
+	return s.QueryWithOptionsCtx(ctx, sql, nil, args...)
+}
+
+func (s *Base) QueryWithOptions(sql string, opts *QueryOptions, args ...interface{}) (*QueryResult, error) {
+	return s.QueryWithOptionsCtx(context.Background(), sql, opts, args...)
+}
+
+func (s *Base) QueryWithOptionsCtx(ctx context.Context, sql string, opts *QueryOptions, args ...interface{}) (*QueryResult, error) {
+	dbURL := s.dtableDbURL()
+	if dbURL == "" {
+		err := fmt.Errorf("dtable-db url unknown, the server may not support SQL queries")
+		return nil, err
+	}
+	url := dbURL + "/api/v1/query/" + s.dtableUUID() + "/"
+
+	data := make(map[string]interface{})
+	data["sql"] = sql
+	data["convert_keys"] = opts == nil || !opts.ColumnKeys
+	if len(args) > 0 {
+		data["parameters"] = args
+	}
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		err := fmt.Errorf("failed to encode post data: %v", err)
+		return nil, err
+	}
+
+	status, body, err := s.httpPost(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to post query to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("POST", url, status, body)
+		return nil, err
+	}
+
+	var ret struct {
+		Success      bool                     `json:"success"`
+		ErrorMessage string                   `json:"error_message"`
+		Results      []map[string]interface{} `json:"results"`
+		Metadata     []*Column                `json:"metadata"`
+	}
+	err = json.Unmarshal(body, &ret)
+	if err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+
+	if !ret.Success {
+		err := fmt.Errorf("failed to query: %s", ret.ErrorMessage)
+		return nil, err
+	}
+
+	return &QueryResult{Columns: ret.Metadata, Rows: ret.Results}, nil
+}
+
+// QueryEach pages through the result of a query without LIMIT clause,
+// pageSize rows at a time, and passes each page to fn.
+func (s *Base) QueryEach(sql string, pageSize int, fn func(*QueryResult) error, args ...interface{}) error {
+	return s.QueryEachCtx(context.Background(), sql, pageSize, fn, args...)
+}
+
+func (s *Base) QueryEachCtx(ctx context.Context, sql string, pageSize int, fn func(*QueryResult) error, args ...interface{}) error {
+	if limitPattern.MatchString(sql) {
+		err := fmt.Errorf("sql must not contain a LIMIT clause when paging")
+		return err
+	}
+	if pageSize <= 0 || pageSize > maxQueryLimit {
+		pageSize = maxQueryLimit
+	}
+	sql = strings.TrimRight(strings.TrimSpace(sql), ";")
+
+	for offset := 0; ; offset += pageSize {
+		page := fmt.Sprintf("%s LIMIT %d OFFSET %d", sql, pageSize, offset)
+		ret, err := s.QueryCtx(ctx, page, args...)
+		if err != nil {
+			return err
+		}
+		if len(ret.Rows) > 0 {
+			if err := fn(ret); err != nil {
+				return err
+			}
+		}
+		if len(ret.Rows) < pageSize {
+			return nil
+		}
+	}
+}
+
+// QueryAll returns all rows of a query without LIMIT clause.
+func (s *Base) QueryAll(sql string, args ...interface{}) (*QueryResult, error) {
+	return s.QueryAllCtx(context.Background(), sql, args...)
+}
+
+func (s *Base) QueryAllCtx(ctx context.Context, sql string, args ...interface{}) (*QueryResult, error) {
+	ret := new(QueryResult)
+	err := s.QueryEachCtx(ctx, sql, maxQueryLimit, func(page *QueryResult) error {
+		if ret.Columns == nil {
+			ret.Columns = page.Columns
+		}
+		ret.Rows = append(ret.Rows, page.Rows...)
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
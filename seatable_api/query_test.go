@@ -0,0 +1,116 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/dtable-db/api/v1/query/"+standInUUID+"/", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["sql"] != "SELECT Name, age FROM Customers WHERE age > ? LIMIT 10" {
+			t.Errorf("unexpected sql %v", req["sql"])
+		}
+		if req["convert_keys"] != true {
+			t.Errorf("expected convert_keys, got %v", req["convert_keys"])
+		}
+		if params, _ := req["parameters"].([]interface{}); len(params) != 1 || params[0] != 18.0 {
+			t.Errorf("unexpected parameters %v", req["parameters"])
+		}
+		writeJSON(w, map[string]interface{}{
+			"success": true,
+			"results": []interface{}{
+				map[string]interface{}{"_id": "r1", "Name": "Ada", "age": 36},
+			},
+			"metadata": []interface{}{
+				map[string]interface{}{"key": "0000", "name": "Name", "type": "text"},
+				map[string]interface{}{"key": "a1b2", "name": "age", "type": "number"},
+			},
+		})
+	})
+	b := si.base(t)
+
+	ret, err := b.Query("SELECT Name, age FROM Customers WHERE age > ? LIMIT 10", 18)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(ret.Columns) != 2 || ret.Columns[1].Type != NUMBER {
+		t.Fatalf("unexpected columns %+v", ret.Columns)
+	}
+	rows := ret.TypedRows()
+	if len(rows) != 1 || rows[0].ID != "r1" {
+		t.Fatalf("unexpected rows %+v", rows)
+	}
+	if age, err := rows[0].Float("age"); err != nil || age != 36 {
+		t.Errorf("expected age 36, got %v, %v", age, err)
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/dtable-db/api/v1/query/"+standInUUID+"/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"success": false, "error_message": "table Foo not found"})
+	})
+	b := si.base(t)
+
+	if _, err := b.Query("SELECT * FROM Foo"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestQueryAll(t *testing.T) {
+	const total = 25
+	pattern := regexp.MustCompile(`^SELECT \* FROM Customers LIMIT (\d+) OFFSET (\d+)$`)
+
+	si := newStandIn(t)
+	si.handle("/dtable-db/api/v1/query/"+standInUUID+"/", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		m := pattern.FindStringSubmatch(req["sql"].(string))
+		if m == nil {
+			t.Errorf("unexpected sql %v", req["sql"])
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		limit, _ := strconv.Atoi(m[1])
+		offset, _ := strconv.Atoi(m[2])
+
+		results := []interface{}{}
+		for i := offset; i < total && i < offset+limit; i++ {
+			results = append(results, map[string]interface{}{"_id": fmt.Sprintf("r%d", i)})
+		}
+		writeJSON(w, map[string]interface{}{"success": true, "results": results})
+	})
+	b := si.base(t)
+
+	var pages, rows int
+	err := b.QueryEach("SELECT * FROM Customers;", 10, func(ret *QueryResult) error {
+		pages++
+		rows += len(ret.Rows)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if pages != 3 || rows != total {
+		t.Errorf("expected %d rows in 3 pages, got %d in %d", total, rows, pages)
+	}
+
+	ret, err := b.QueryAll("SELECT * FROM Customers")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(ret.Rows) != total || ret.Rows[total-1]["_id"] != "r24" {
+		t.Errorf("unexpected rows %v", ret.Rows)
+	}
+
+	if err := b.QueryEach("SELECT * FROM Customers LIMIT 5", 10, nil); err == nil {
+		t.Error("expected an error for a query with LIMIT")
+	}
+}
@@ -0,0 +1,176 @@
+package seatable_api
+
+import (
+	"context"
+	"fmt"
+)
+
+// QuerySet holds the rows of a table that match a condition string, see
+// Base.Filter. Filter narrows a QuerySet down further without requesting
+// the rows again, while Update and Delete write to the base.
+type QuerySet struct {
+	Conditions string
+	Rows       []*Row
+
+	base      *Base
+	tableName string
+	table     *Table
+}
+
+func NewQuerySet(base *Base, table *Table) *QuerySet {
+	return &QuerySet{base: base, tableName: table.Name, table: table}
+}
+
+// ExecuteConditions replaces Rows by the rows matching Conditions.
+func (q *QuerySet) ExecuteConditions() error {
+	if q.Conditions == "" {
+		return nil
+	}
+
+	cond, err := parseConditions(q.Conditions, q.table)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]*Row, 0, len(q.Rows))
+	for _, row := range q.Rows {
+		ok, err := cond.match(row)
+		if err != nil {
+			return err
+		}
+		if ok {
+			rows = append(rows, row)
+		}
+	}
+	q.Rows = rows
+	return nil
+}
+
+func (q *QuerySet) clone() *QuerySet {
+	rows := make([]*Row, len(q.Rows))
+	copy(rows, q.Rows)
+	return &QuerySet{
+		Conditions: q.Conditions,
+		Rows:       rows,
+		base:       q.base,
+		tableName:  q.tableName,
+		table:      q.table,
+	}
+}
+
+// Filter returns a new QuerySet with the rows of q that also match
+// conditions.
+func (q *QuerySet) Filter(conditions string) (*QuerySet, error) {
+	ret := q.clone()
+	ret.Conditions = conditions
+	if err := ret.ExecuteConditions(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Get returns the only row of q matching conditions, which may be empty.
+// It fails with an error wrapping ErrNotFound when no row matches.
+func (q *QuerySet) Get(conditions string) (*Row, error) {
+	ret, err := q.Filter(conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(ret.Rows) {
+	case 0:
+		err := fmt.Errorf("row matching %q: %w", conditions, ErrNotFound)
+		return nil, err
+	case 1:
+		return ret.Rows[0], nil
+	}
+	err = fmt.Errorf("%d rows match %q, expect one", len(ret.Rows), conditions)
+	return nil, err
+}
+
+func (q *QuerySet) All() []*Row {
+	return q.clone().Rows
+}
+
+func (q *QuerySet) First() *Row {
+	if len(q.Rows) == 0 {
+		return nil
+	}
+	return q.Rows[0]
+}
+
+func (q *QuerySet) Last() *Row {
+	if len(q.Rows) == 0 {
+		return nil
+	}
+	return q.Rows[len(q.Rows)-1]
+}
+
+func (q *QuerySet) Count() int {
+	return len(q.Rows)
+}
+
+func (q *QuerySet) Exists() bool {
+	return len(q.Rows) > 0
+}
+
+// Update writes rowData, a map or tagged struct, to all rows of q and
+// returns the updated rows.
+func (q *QuerySet) Update(rowData interface{}) ([]*Row, error) {
+	return q.UpdateCtx(context.Background(), rowData)
+}
+
+func (q *QuerySet) UpdateCtx(ctx context.Context, rowData interface{}) ([]*Row, error) {
+	data, err := toRowData(rowData)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := data.(map[string]interface{})
+	if !ok {
+		err := fmt.Errorf("can not update rows with %T, expect a map or struct", rowData)
+		return nil, err
+	}
+
+	updates := make([]RowUpdate, 0, len(q.Rows))
+	for _, row := range q.Rows {
+		updates = append(updates, RowUpdate{RowID: row.ID, Row: values})
+	}
+	if _, err := q.base.BatchUpdateRowsCtx(ctx, q.tableName, updates); err != nil {
+		return nil, err
+	}
+
+	for _, row := range q.Rows {
+		for k, v := range values {
+			row.Values[k] = v
+		}
+	}
+	return q.All(), nil
+}
+
+// Delete deletes all rows of q from the table and returns their number.
+func (q *QuerySet) Delete() (int, error) {
+	return q.DeleteCtx(context.Background())
+}
+
+func (q *QuerySet) DeleteCtx(ctx context.Context) (int, error) {
+	if len(q.Rows) == 0 {
+		return 0, nil
+	}
+
+	rowIDs := make([]string, 0, len(q.Rows))
+	for _, row := range q.Rows {
+		rowIDs = append(rowIDs, row.ID)
+	}
+
+	ret, err := q.base.BatchDeleteRowsCtx(ctx, q.tableName, rowIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	count := len(rowIDs)
+	if n, ok := ret["deleted_rows"].(float64); ok {
+		count = int(n)
+	}
+	q.Rows = nil
+	return count, nil
+}
@@ -0,0 +1,127 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestQuerySet(t *testing.T) {
+	table := testTable(t, "table1")
+	rows := []interface{}{
+		map[string]interface{}{"_id": "r1", "Name": "Ada", "age": 36, "Status": "Open"},
+		map[string]interface{}{"_id": "r2", "Name": "Bob", "age": 17, "Status": "Done"},
+		map[string]interface{}{"_id": "r3", "Name": "Cy", "age": 52, "Status": "Open"},
+	}
+	var updated, deleted []string
+
+	si := newStandIn(t)
+	si.handle("/columns/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"columns": table.Columns})
+	})
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"rows": rows})
+	})
+	si.handle("/batch-update-rows/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Updates []struct {
+				RowID string `json:"row_id"`
+			} `json:"updates"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		for _, u := range req.Updates {
+			updated = append(updated, u.RowID)
+		}
+		writeJSON(w, map[string]interface{}{"success": true})
+	})
+	si.handle("/batch-delete-rows/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			RowIDs []string `json:"row_ids"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		deleted = req.RowIDs
+		writeJSON(w, map[string]interface{}{"deleted_rows": len(req.RowIDs)})
+	})
+	b := si.base(t)
+
+	qs, err := b.Filter("table1", "Status = 'Open'", "")
+	if err != nil {
+		t.Fatalf("filter failed: %v", err)
+	}
+	if qs.Count() != 2 || !qs.Exists() || qs.First().ID != "r1" || qs.Last().ID != "r3" {
+		t.Fatalf("unexpected rows %v", qs.All())
+	}
+
+	row, err := qs.Get("age > 40")
+	if err != nil || row.ID != "r3" {
+		t.Errorf("Get = %v, %v", row, err)
+	}
+	if _, err := qs.Get("age > 100"); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if _, err := qs.Get(""); err == nil {
+		t.Error("expected an error for multiple rows")
+	}
+
+	adults, err := qs.Filter("age >= 18 and Name like 'a%'")
+	if err != nil || adults.Count() != 1 {
+		t.Fatalf("chained filter = %v, %v", adults, err)
+	}
+	if qs.Count() != 2 {
+		t.Errorf("chained filter changed its parent")
+	}
+
+	ret, err := adults.Update(map[string]interface{}{"Status": "Done"})
+	if err != nil || len(ret) != 1 || len(updated) != 1 || updated[0] != "r1" {
+		t.Fatalf("update = %v, %v, updated %v", ret, err, updated)
+	}
+	if s, _ := ret[0].String("Status"); s != "Done" {
+		t.Errorf("updated row has Status %q", s)
+	}
+
+	n, err := qs.Delete()
+	if err != nil || n != 2 || len(deleted) != 2 || qs.Exists() {
+		t.Errorf("delete = %d, %v, deleted %v", n, err, deleted)
+	}
+
+	if _, err := b.Filter("table1", "Missing = 1", ""); err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+}
+
+func TestFilterPagesThroughRows(t *testing.T) {
+	const total = 2500
+
+	table := testTable(t, "table1")
+	si := newStandIn(t)
+	si.handle("/columns/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"columns": table.Columns})
+	})
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit == 0 || limit > defaultPageSize {
+			limit = defaultPageSize
+		}
+		rows := []interface{}{}
+		for i := start; i < start+limit && i < total; i++ {
+			rows = append(rows, map[string]interface{}{"_id": fmt.Sprintf("row%d", i), "age": i})
+		}
+		writeJSON(w, map[string]interface{}{"rows": rows})
+	})
+	b := si.base(t)
+
+	qs, err := b.Filter("table1", "", "")
+	if err != nil {
+		t.Fatalf("filter failed: %v", err)
+	}
+	if qs.Count() != total {
+		t.Errorf("filter returned %d rows, want %d", qs.Count(), total)
+	}
+	row, err := qs.Get("age = 2400")
+	if err != nil || row.ID != "row2400" {
+		t.Errorf("Get = %v, %v", row, err)
+	}
+}
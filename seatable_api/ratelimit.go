@@ -0,0 +1,141 @@
+package seatable_api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit describes a client-side request budget of Requests per Per,
+// e.g. RateLimit{Requests: 300, Per: time.Minute}. Burst defaults to
+// Requests. When NoWait is set an exhausted budget fails the request
+// with a *RateLimitError instead of blocking until a token is available.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+	NoWait   bool
+}
+
+type RateLimitError struct {
+	Budget string
+	Wait   time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s rate limit exceeded, retry in %v", e.Budget, e.Wait)
+}
+
+// WithServerRateLimit limits requests to dtable-server and dtable-db. The
+// limiter is shared by all clones of the Base.
+func WithServerRateLimit(l RateLimit) Option {
+	return func(s *Base) {
+		s.rateLimits().server = newRateLimiter("dtable-server", l)
+	}
+}
+
+// WithWebRateLimit limits requests to dtable-web, including the file
+// upload and download link endpoints.
+func WithWebRateLimit(l RateLimit) Option {
+	return func(s *Base) {
+		s.rateLimits().web = newRateLimiter("dtable-web", l)
+	}
+}
+
+type rateLimits struct {
+	server *rateLimiter
+	web    *rateLimiter
+}
+
+func (s *Base) rateLimits() *rateLimits {
+	if s.limits == nil {
+		s.limits = new(rateLimits)
+	}
+	return s.limits
+}
+
+func (s *Base) waitRateLimit(ctx context.Context, url string) error {
+	if s.limits == nil {
+		return nil
+	}
+
+	var l *rateLimiter
+	if hasURLPrefix(url, s.dtableServerURL()) || hasURLPrefix(url, s.dtableDbURL()) {
+		l = s.limits.server
+	} else if strings.HasPrefix(url, s.ServerURL) {
+		l = s.limits.web
+	}
+	if l == nil {
+		return nil
+	}
+	return l.wait(ctx)
+}
+
+func hasURLPrefix(url, prefix string) bool {
+	return prefix != "" && strings.HasPrefix(url, prefix)
+}
+
+// rateLimiter is a token bucket.
+type rateLimiter struct {
+	name   string
+	rate   float64
+	burst  float64
+	noWait bool
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(name string, l RateLimit) *rateLimiter {
+	if l.Requests <= 0 || l.Per <= 0 {
+		return nil
+	}
+	burst := l.Burst
+	if burst <= 0 {
+		burst = l.Requests
+	}
+
+	return &rateLimiter{
+		name:   name,
+		rate:   float64(l.Requests) / l.Per.Seconds(),
+		burst:  float64(burst),
+		noWait: l.NoWait,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+
+	wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	if l.noWait {
+		l.mu.Unlock()
+		return &RateLimitError{Budget: l.name, Wait: wait}
+	}
+	l.tokens--
+	l.mu.Unlock()
+
+	if err := sleepContext(ctx, wait); err != nil {
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package seatable_api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurstAndRefill(t *testing.T) {
+	l := newRateLimiter("test", RateLimit{Requests: 10, Per: 500 * time.Millisecond, Burst: 3})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(ctx); err != nil {
+			t.Fatalf("wait failed: %v", err)
+		}
+	}
+	if d := time.Since(start); d > 20*time.Millisecond {
+		t.Errorf("burst of 3 took %v", d)
+	}
+
+	// The bucket is empty, one token refills every 50ms.
+	start = time.Now()
+	if err := l.wait(ctx); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+	if d := time.Since(start); d < 30*time.Millisecond {
+		t.Errorf("expected to wait for a token, waited %v", d)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(ctx); err != nil {
+			t.Fatalf("wait failed: %v", err)
+		}
+	}
+	if d := time.Since(start); d > 20*time.Millisecond {
+		t.Errorf("refilled burst took %v", d)
+	}
+}
+
+func TestRateLimiterNoWait(t *testing.T) {
+	l := newRateLimiter("dtable-server", RateLimit{Requests: 1, Per: time.Hour, NoWait: true})
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+
+	err := l.wait(context.Background())
+	var e *RateLimitError
+	if !errors.As(err, &e) || e.Budget != "dtable-server" || e.Wait <= 0 || !IsRateLimited(err) {
+		t.Errorf("expected a rate limit error, got %v", err)
+	}
+}
+
+func TestRateLimiterRefundsOnCancel(t *testing.T) {
+	l := newRateLimiter("test", RateLimit{Requests: 1, Per: time.Hour})
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.tokens < -0.01 {
+		t.Errorf("token of cancelled wait not refunded, %v tokens left", l.tokens)
+	}
+}
+
+func TestRateLimitBudgets(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"rows": []interface{}{}})
+	})
+	limit := RateLimit{Requests: 2, Per: time.Hour, NoWait: true}
+	// Auth takes the first dtable-web token.
+	b := si.base(t, WithServerRateLimit(limit), WithWebRateLimit(limit))
+	c, err := b.Clone()
+	if err != nil {
+		t.Fatalf("failed to clone base: %v", err)
+	}
+	ctx := context.Background()
+
+	budget := func(err error) string {
+		var e *RateLimitError
+		if errors.As(err, &e) {
+			return e.Budget
+		}
+		return ""
+	}
+
+	// dtable-server and dtable-db requests share a budget, which the clone
+	// shares with the original.
+	if _, err := b.ListRows(tableName, ""); err != nil {
+		t.Fatalf("failed to list rows: %v", err)
+	}
+	if err := c.waitRateLimit(ctx, c.dtableDbURL()+"/api/v1/query/"); err != nil {
+		t.Fatalf("dtable-db request limited: %v", err)
+	}
+	if _, err := c.ListRows(tableName, ""); budget(err) != "dtable-server" {
+		t.Errorf("expected the dtable-server budget to be exhausted, got %v", err)
+	}
+
+	// dtable-web has its own budget, other hosts are not limited.
+	if err := b.waitRateLimit(ctx, b.ServerURL+"/api/v2.1/dtable/app-upload-link/"); err != nil {
+		t.Errorf("dtable-web request limited: %v", err)
+	}
+	if err := c.waitRateLimit(ctx, c.ServerURL+"/api/v2.1/dtable/app-download-link/"); budget(err) != "dtable-web" {
+		t.Errorf("expected the dtable-web budget to be exhausted, got %v", err)
+	}
+	if err := b.waitRateLimit(ctx, "https://files.example.com/upload"); err != nil {
+		t.Errorf("request to another host limited: %v", err)
+	}
+}
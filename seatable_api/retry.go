@@ -0,0 +1,149 @@
+package seatable_api
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests are retried after rate limiting
+// (429) or a temporarily unavailable server (502, 503, 504). The zero
+// value disables retries.
+//
+// A POST may already have been applied by the server when the gateway
+// fails or the connection breaks, and replaying it could e.g. append a
+// row twice. POST requests are therefore only retried after 429 and 503,
+// which SeaTable sends before handling the request; network errors are
+// only retried for GET, PUT and DELETE requests.
+//
+// A Retry-After header sets the delay before the next attempt. A delay
+// longer than MaxBackoff is not waited for; the response is returned as
+// an *APIError instead.
+type RetryPolicy struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+}
+
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(s *Base) {
+		s.retry = p
+	}
+}
+
+func (p RetryPolicy) shouldRetry(req *http.Request, attempt, status int, header http.Header, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if req.Context().Err() != nil {
+		return 0, false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0, false
+	}
+
+	if err != nil {
+		switch req.Method {
+		case http.MethodGet, http.MethodPut, http.MethodDelete:
+			return p.backoff(attempt), true
+		}
+		return 0, false
+	}
+
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		if req.Method == http.MethodPost {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if wait, ok := parseRetryAfter(header); ok {
+		if _, max := p.bounds(); wait > max {
+			return 0, false
+		}
+		return wait, true
+	}
+	return p.backoff(attempt), true
+}
+
+func (p RetryPolicy) bounds() (time.Duration, time.Duration) {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = DefaultRetryPolicy.MinBackoff
+	}
+	if max < min {
+		max = min
+	}
+	return min, max
+}
+
+// backoff returns an exponentially growing delay with full jitter.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	min, max := p.bounds()
+
+	d := min
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return time.Duration(rand.Int63n(int64(d)) + 1)
+}
+
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		err := fmt.Errorf("failed to rewind request body: %v", err)
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,238 @@
+package seatable_api
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fastRetry retries quickly so that tests don't wait for real backoffs.
+var fastRetry = RetryPolicy{MaxAttempts: 4, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+// attempts counts the requests seen by a stand-in handler and records
+// their bodies.
+type attempts struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (a *attempts) add(r *http.Request) int {
+	body, _ := ioutil.ReadAll(r.Body)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.bodies = append(a.bodies, string(body))
+	return len(a.bodies)
+}
+
+func (a *attempts) count() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.bodies)
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: time.Second, MaxBackoff: 8 * time.Second}
+
+	var longest time.Duration
+	for i := 0; i < 200; i++ {
+		for attempt := 1; attempt <= 10; attempt++ {
+			d := p.backoff(attempt)
+			limit := time.Second << uint(attempt-1)
+			if limit > p.MaxBackoff {
+				limit = p.MaxBackoff
+			}
+			if d <= 0 || d > limit {
+				t.Fatalf("backoff(%d) = %v, want in (0, %v]", attempt, d, limit)
+			}
+		}
+		if d := p.backoff(3); d > longest {
+			longest = d
+		}
+	}
+	if longest <= 2*time.Second {
+		t.Errorf("backoff does not grow, longest third backoff %v", longest)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	date := time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat)
+	tests := []struct {
+		value string
+		min   time.Duration
+		max   time.Duration
+		ok    bool
+	}{
+		{"", 0, 0, false},
+		{"7", 7 * time.Second, 7 * time.Second, true},
+		{"-1", 0, 0, false},
+		{date, 80 * time.Second, 90 * time.Second, true},
+		{"Mon, 01 Jan 2001 00:00:00 GMT", 0, 0, true},
+		{"soon", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		d, ok := parseRetryAfter(header)
+		if ok != tt.ok || d < tt.min || d > tt.max {
+			t.Errorf("parseRetryAfter(%q) = %v, %v", tt.value, d, ok)
+		}
+	}
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	si := newStandIn(t)
+	var seen attempts
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		if seen.add(r) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"success": true})
+	})
+	b := si.base(t, WithRetryPolicy(fastRetry))
+
+	if _, err := b.UpdateRow(tableName, "row1", map[string]interface{}{"Name": "name1"}); err != nil {
+		t.Fatalf("failed to update row: %v", err)
+	}
+	if seen.count() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", seen.count())
+	}
+	for _, body := range seen.bodies {
+		if body != seen.bodies[0] || !strings.Contains(body, "name1") {
+			t.Errorf("body not replayed: %q", seen.bodies)
+			break
+		}
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	si := newStandIn(t)
+	var seen attempts
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		seen.add(r)
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	b := si.base(t, WithRetryPolicy(fastRetry))
+
+	_, err := b.UpdateRow(tableName, "row1", map[string]interface{}{"Name": "name1"})
+	if !IsRateLimited(err) || seen.count() != fastRetry.MaxAttempts {
+		t.Errorf("got %v after %d attempts", err, seen.count())
+	}
+}
+
+func TestRetryAfterLongerThanMaxBackoff(t *testing.T) {
+	si := newStandIn(t)
+	var seen attempts
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		seen.add(r)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	b := si.base(t, WithRetryPolicy(fastRetry))
+
+	start := time.Now()
+	_, err := b.ListRows(tableName, "")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected a 429 APIError, got %v", err)
+	}
+	if seen.count() != 1 || time.Since(start) > time.Second {
+		t.Errorf("waited for Retry-After: %d attempts in %v", seen.count(), time.Since(start))
+	}
+}
+
+func TestRetryPost(t *testing.T) {
+	si := newStandIn(t)
+	var seen attempts
+	var status int
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		if seen.add(r) == 1 {
+			w.WriteHeader(status)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"_id": "row1"})
+	})
+	b := si.base(t, WithRetryPolicy(fastRetry))
+
+	tests := []struct {
+		status   int
+		attempts int
+	}{
+		{http.StatusTooManyRequests, 2},
+		{http.StatusServiceUnavailable, 2},
+		{http.StatusBadGateway, 1},
+		{http.StatusGatewayTimeout, 1},
+	}
+	for _, tt := range tests {
+		seen = attempts{}
+		status = tt.status
+		_, err := b.AppendRow(tableName, map[string]interface{}{"Name": "name1"})
+		if seen.count() != tt.attempts {
+			t.Errorf("status %d: %d attempts, want %d", tt.status, seen.count(), tt.attempts)
+		}
+		if (tt.attempts == 1) != (err != nil) {
+			t.Errorf("status %d: unexpected error %v", tt.status, err)
+		}
+	}
+}
+
+// failingTransport fails every request with a network error.
+type failingTransport struct {
+	mu      sync.Mutex
+	methods []string
+}
+
+func (f *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.methods = append(f.methods, req.Method)
+	return nil, errors.New("connection reset")
+}
+
+func TestRetryNetworkError(t *testing.T) {
+	si := newStandIn(t)
+	b := si.base(t, WithRetryPolicy(fastRetry))
+	ft := new(failingTransport)
+	b.httpClient = &http.Client{Transport: ft}
+
+	if _, err := b.ListRows(tableName, ""); err == nil {
+		t.Error("expected an error")
+	}
+	if len(ft.methods) != fastRetry.MaxAttempts {
+		t.Errorf("GET attempted %d times, want %d", len(ft.methods), fastRetry.MaxAttempts)
+	}
+
+	ft.methods = nil
+	if _, err := b.AppendRow(tableName, map[string]interface{}{"Name": "name1"}); err == nil {
+		t.Error("expected an error")
+	}
+	if len(ft.methods) != 1 {
+		t.Errorf("POST attempted %d times, want 1", len(ft.methods))
+	}
+}
+
+func TestRetryNonRewindableBody(t *testing.T) {
+	si := newStandIn(t)
+	var seen attempts
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		seen.add(r)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	b := si.base(t, WithRetryPolicy(fastRetry))
+
+	// A reader other than bytes.Buffer, bytes.Reader or strings.Reader
+	// leaves the request without GetBody.
+	body := ioutil.NopCloser(strings.NewReader(`{"row": {}}`))
+	status, _, err := b.httpPut(context.Background(), b.dtableURL("/rows/"), b.authHeaders(), body)
+	if err != nil || status != http.StatusServiceUnavailable || seen.count() != 1 {
+		t.Errorf("got %d, %v after %d attempts", status, err, seen.count())
+	}
+}
@@ -0,0 +1,618 @@
+package seatable_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoValue is returned by the Row accessors when a cell is empty and
+// the accessor has no natural zero value for it.
+var ErrNoValue = errors.New("cell has no value")
+
+// Row is a table row whose cells are converted according to the column
+// types of its table. Cells are looked up by column name or key.
+type Row struct {
+	ID     string
+	Values map[string]interface{}
+
+	table *Table
+}
+
+type FileValue struct {
+	Name string  `json:"name"`
+	Size float64 `json:"size"`
+	Type string  `json:"type"`
+	URL  string  `json:"url"`
+}
+
+type LinkValue struct {
+	RowID        string      `json:"row_id"`
+	DisplayValue interface{} `json:"display_value"`
+}
+
+type GeolocationValue struct {
+	Province      string  `json:"province,omitempty"`
+	City          string  `json:"city,omitempty"`
+	District      string  `json:"district,omitempty"`
+	Detail        string  `json:"detail,omitempty"`
+	CountryRegion string  `json:"country_region,omitempty"`
+	Lng           float64 `json:"lng,omitempty"`
+	Lat           float64 `json:"lat,omitempty"`
+}
+
+// NewRow wraps the values of a row as returned by the API. table may be
+// nil, in which case cells are converted by their JSON type only.
+func NewRow(table *Table, values map[string]interface{}) *Row {
+	id, _ := values["_id"].(string)
+	return &Row{ID: id, Values: values, table: table}
+}
+
+// DecodeRows converts the result of ListRows or FilterRows.
+func DecodeRows(table *Table, rows interface{}) ([]*Row, error) {
+	if rows == nil {
+		return nil, nil
+	}
+	list, ok := rows.([]interface{})
+	if !ok {
+		err := fmt.Errorf("failed to assert rows")
+		return nil, err
+	}
+
+	ret := make([]*Row, 0, len(list))
+	for _, v := range list {
+		values, ok := v.(map[string]interface{})
+		if !ok {
+			err := fmt.Errorf("failed to assert row")
+			return nil, err
+		}
+		ret = append(ret, NewRow(table, values))
+	}
+	return ret, nil
+}
+
+func (r *Row) Table() *Table {
+	return r.table
+}
+
+// Value returns the raw value of a cell and its column, which is nil
+// when the row has no table.
+func (r *Row) Value(column string) (interface{}, *Column, error) {
+	if r.table == nil {
+		return r.Values[column], nil, nil
+	}
+
+	col := r.table.ColumnByName(column)
+	if col == nil {
+		col = r.table.ColumnByKey(column)
+	}
+	if col == nil {
+		err := fmt.Errorf("column %s not found in table %s", column, r.table.Name)
+		return nil, nil, err
+	}
+
+	v, ok := r.Values[col.Name]
+	if !ok {
+		v = r.Values[col.Key]
+	}
+	return v, col, nil
+}
+
+// resultType returns the type a cell value is converted as. Formula
+// columns are treated like a column of their result type.
+func resultType(col *Column) ColumnTypes {
+	if col == nil {
+		return ""
+	}
+	if (col.Type == FORMULA || col.Type == LINK_FORMULA) && col.Data != nil {
+		switch col.Data.ResultType {
+		case "number":
+			return NUMBER
+		case "date":
+			return DATE
+		case "bool":
+			return CHECKBOX
+		case "string":
+			return TEXT
+		}
+	}
+	return col.Type
+}
+
+func checkType(column string, col *Column, types ...ColumnTypes) error {
+	if col == nil {
+		return nil
+	}
+	t := resultType(col)
+	for _, v := range types {
+		if t == v {
+			return nil
+		}
+	}
+	err := fmt.Errorf("column %s of type %s can not be read as %s", column, col.Type, types[0])
+	return err
+}
+
+func (r *Row) String(column string) (string, error) {
+	v, col, err := r.Value(column)
+	if err != nil {
+		return "", err
+	}
+
+	switch resultType(col) {
+	case IMAGE, FILE, LINK, MULTIPLE_SELECT, GEOLOCATION, COLLABORATOR:
+		err := fmt.Errorf("column %s of type %s can not be read as string", column, col.Type)
+		return "", err
+	case SINGLE_SELECT:
+		if s, ok := v.(string); ok {
+			if o := col.OptionByID(s); o != nil {
+				return o.Name, nil
+			}
+		}
+	}
+
+	switch s := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return s, nil
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(s), nil
+	case json.Number:
+		return s.String(), nil
+	}
+	err = fmt.Errorf("column %s: can not convert %T to string", column, v)
+	return "", err
+}
+
+func (r *Row) Float(column string) (float64, error) {
+	v, col, err := r.Value(column)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkType(column, col, NUMBER, RATING, DURATION, AUTO_NUMBER); err != nil {
+		return 0, err
+	}
+
+	switch n := v.(type) {
+	case nil:
+		return 0, ErrNoValue
+	case float64:
+		return n, nil
+	case json.Number:
+		return n.Float64()
+	case string:
+		if n == "" {
+			return 0, ErrNoValue
+		}
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			err := fmt.Errorf("column %s: %v", column, err)
+			return 0, err
+		}
+		return f, nil
+	}
+	err = fmt.Errorf("column %s: can not convert %T to number", column, v)
+	return 0, err
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999-07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+	}
+	err := fmt.Errorf("invalid time %q", s)
+	return time.Time{}, err
+}
+
+// Time parses date, ctime and mtime cells. Dates without a time zone are
+// returned in UTC.
+func (r *Row) Time(column string) (time.Time, error) {
+	v, col, err := r.Value(column)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := checkType(column, col, DATE, CTIME, MTIME); err != nil {
+		return time.Time{}, err
+	}
+
+	s, ok := v.(string)
+	if !ok && v != nil {
+		err := fmt.Errorf("column %s: can not convert %T to time", column, v)
+		return time.Time{}, err
+	}
+	if s == "" {
+		return time.Time{}, ErrNoValue
+	}
+
+	t, err := parseTime(s)
+	if err != nil {
+		err := fmt.Errorf("column %s: %v", column, err)
+		return time.Time{}, err
+	}
+	return t, nil
+}
+
+func (r *Row) Bool(column string) (bool, error) {
+	v, col, err := r.Value(column)
+	if err != nil {
+		return false, err
+	}
+	if err := checkType(column, col, CHECKBOX); err != nil {
+		return false, err
+	}
+
+	switch b := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return b, nil
+	}
+	err = fmt.Errorf("column %s: can not convert %T to bool", column, v)
+	return false, err
+}
+
+// Strings returns the option names of a multiple-select cell.
+func (r *Row) Strings(column string) ([]string, error) {
+	v, col, err := r.Value(column)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkType(column, col, MULTIPLE_SELECT); err != nil {
+		return nil, err
+	}
+
+	ret, err := toStrings(column, v)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range ret {
+		if col != nil {
+			if o := col.OptionByID(s); o != nil {
+				ret[i] = o.Name
+			}
+		}
+	}
+	return ret, nil
+}
+
+// Collaborators returns the user ids (emails) of a collaborator, creator
+// or last-modifier cell.
+func (r *Row) Collaborators(column string) ([]string, error) {
+	v, col, err := r.Value(column)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkType(column, col, COLLABORATOR, CREATOR, LAST_MODIFIER); err != nil {
+		return nil, err
+	}
+
+	if s, ok := v.(string); ok {
+		if s == "" {
+			return nil, nil
+		}
+		return []string{s}, nil
+	}
+	return toStrings(column, v)
+}
+
+func (r *Row) Images(column string) ([]string, error) {
+	v, col, err := r.Value(column)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkType(column, col, IMAGE); err != nil {
+		return nil, err
+	}
+	return toStrings(column, v)
+}
+
+func (r *Row) Files(column string) ([]*FileValue, error) {
+	v, col, err := r.Value(column)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkType(column, col, FILE); err != nil {
+		return nil, err
+	}
+
+	var ret []*FileValue
+	if err := convertValue(v, &ret); err != nil {
+		err := fmt.Errorf("column %s: %v", column, err)
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Links returns the linked rows of a link cell. Rows listed without
+// display values, as with convert_link_id disabled, only have RowID set.
+func (r *Row) Links(column string) ([]*LinkValue, error) {
+	v, col, err := r.Value(column)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkType(column, col, LINK); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	list, ok := v.([]interface{})
+	if !ok {
+		err := fmt.Errorf("column %s: can not convert %T to links", column, v)
+		return nil, err
+	}
+
+	ret := make([]*LinkValue, 0, len(list))
+	for _, item := range list {
+		if id, ok := item.(string); ok {
+			ret = append(ret, &LinkValue{RowID: id})
+			continue
+		}
+		link := new(LinkValue)
+		if err := convertValue(item, link); err != nil {
+			err := fmt.Errorf("column %s: %v", column, err)
+			return nil, err
+		}
+		ret = append(ret, link)
+	}
+	return ret, nil
+}
+
+func (r *Row) Geolocation(column string) (*GeolocationValue, error) {
+	v, col, err := r.Value(column)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkType(column, col, GEOLOCATION); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	ret := new(GeolocationValue)
+	if err := convertValue(v, ret); err != nil {
+		err := fmt.Errorf("column %s: %v", column, err)
+		return nil, err
+	}
+	return ret, nil
+}
+
+func toStrings(column string, v interface{}) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		err := fmt.Errorf("column %s: can not convert %T to list", column, v)
+		return nil, err
+	}
+
+	ret := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			err := fmt.Errorf("column %s: can not convert %T to string", column, item)
+			return nil, err
+		}
+		ret = append(ret, s)
+	}
+	return ret, nil
+}
+
+// convertValue re-decodes a generic JSON value into dst.
+func convertValue(v interface{}, dst interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+// GetTable returns the metadata of a single table.
+func (s *Base) GetTable(tableName string) (*Table, error) {
+	return s.GetTableCtx(context.Background(), tableName)
+}
+
+func (s *Base) GetTableCtx(ctx context.Context, tableName string) (*Table, error) {
+	md, err := s.GetMetadataCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	table := md.TableByName(tableName)
+	if table == nil {
+		err := fmt.Errorf("table %s: %w", tableName, ErrNotFound)
+		return nil, err
+	}
+	return table, nil
+}
+
+func (s *Base) ListTypedRows(tableName, viewName string) ([]*Row, error) {
+	return s.ListTypedRowsCtx(context.Background(), tableName, viewName)
+}
+
+func (s *Base) ListTypedRowsCtx(ctx context.Context, tableName, viewName string) ([]*Row, error) {
+	table, err := s.GetTableCtx(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.ListRowsCtx(ctx, tableName, viewName)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeRows(table, rows)
+}
+
+func (s *Base) FilterTypedRows(tableName string, filters []map[string]interface{}, viewName string, filterConjunction string) ([]*Row, error) {
+	return s.FilterTypedRowsCtx(context.Background(), tableName, filters, viewName, filterConjunction)
+}
+
+func (s *Base) FilterTypedRowsCtx(ctx context.Context, tableName string, filters []map[string]interface{}, viewName string, filterConjunction string) ([]*Row, error) {
+	table, err := s.GetTableCtx(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.FilterRowsCtx(ctx, tableName, filters, viewName, filterConjunction)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeRows(table, rows)
+}
+
+// GetRow returns a single row by its id. A missing row results in a
+// *RowNotFoundError.
+func (s *Base) GetRow(tableName, rowID string) (*Row, error) {
+	return s.GetRowCtx(context.Background(), tableName, rowID)
+}
+
+func (s *Base) GetRowCtx(ctx context.Context, tableName, rowID string) (*Row, error) {
+	table, err := s.GetTableCtx(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return s.getRow(ctx, table, rowID)
+}
+
+// GetRows returns the rows with the given ids in the order of ids.
+// Missing rows are left out and reported by a *RowNotFoundError along
+// with the rows found. The rows are fetched from dtable-db with one
+// query per 1000 ids; servers without dtable-db get one request per row.
+func (s *Base) GetRows(tableName string, rowIDs []string) ([]*Row, error) {
+	return s.GetRowsCtx(context.Background(), tableName, rowIDs)
+}
+
+func (s *Base) GetRowsCtx(ctx context.Context, tableName string, rowIDs []string) ([]*Row, error) {
+	table, err := s.GetTableCtx(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]*Row, len(rowIDs))
+	if s.dtableDbURL() == "" {
+		for _, id := range rowIDs {
+			row, err := s.getRow(ctx, table, id)
+			var notFound *RowNotFoundError
+			if errors.As(err, &notFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			found[id] = row
+		}
+	} else {
+		pages := make([][]map[string]interface{}, (len(rowIDs)+maxBatchRows-1)/maxBatchRows)
+		ret := runBatch(ctx, len(rowIDs), nil, func(ctx context.Context, start, end int) (map[string]interface{}, error) {
+			rows, err := s.queryRows(ctx, table, rowIDs[start:end])
+			pages[start/maxBatchRows] = rows
+			return nil, err
+		})
+		if err := ret.Err(); err != nil {
+			return nil, err
+		}
+		for _, page := range pages {
+			for _, values := range page {
+				row := NewRow(table, values)
+				found[row.ID] = row
+			}
+		}
+	}
+
+	rows := make([]*Row, 0, len(rowIDs))
+	var missing []string
+	for _, id := range rowIDs {
+		if row, ok := found[id]; ok {
+			rows = append(rows, row)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		err := &RowNotFoundError{Table: tableName, RowIDs: missing}
+		return rows, err
+	}
+	return rows, nil
+}
+
+// queryRows selects the rows with the given ids through dtable-db.
+func (s *Base) queryRows(ctx context.Context, table *Table, rowIDs []string) ([]map[string]interface{}, error) {
+	args := make([]interface{}, 0, len(rowIDs))
+	for _, id := range rowIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(rowIDs)), ", ")
+	name := strings.Replace(table.Name, "`", "``", -1)
+	sql := fmt.Sprintf("SELECT * FROM `%s` WHERE _id IN (%s) LIMIT %d", name, placeholders, len(rowIDs))
+
+	ret, err := s.QueryCtx(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return ret.Rows, nil
+}
+
+func (s *Base) getRow(ctx context.Context, table *Table, rowID string) (*Row, error) {
+	url := s.dtableURL("/rows/" + neturl.PathEscape(rowID) + "/")
+
+	params := neturl.Values{}
+	params.Add("table_name", table.Name)
+
+	status, body, err := s.httpGet(ctx, url, params.Encode(), s.authHeaders(), nil)
+	if err != nil {
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
+		return nil, err
+	}
+
+	if status == http.StatusNotFound {
+		err := &RowNotFoundError{Table: table.Name, RowIDs: []string{rowID}}
+		return nil, err
+	}
+	if status >= 400 {
+		err := newAPIError("GET", url, status, body)
+		return nil, err
+	}
+
+	rsp, err := parseResponse(body)
+	if err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+
+	ret, ok := rsp.(map[string]interface{})
+	if !ok {
+		err := fmt.Errorf("failed to assert response")
+		return nil, err
+	}
+
+	// Some servers answer a missing row with an empty object.
+	if _, ok := ret["_id"]; !ok {
+		err := &RowNotFoundError{Table: table.Name, RowIDs: []string{rowID}}
+		return nil, err
+	}
+	return NewRow(table, ret), nil
+}
@@ -0,0 +1,201 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func testTable(t *testing.T, name string) *Table {
+	var ret struct {
+		Metadata *Metadata `json:"metadata"`
+	}
+	if err := json.Unmarshal([]byte(testMetadata), &ret); err != nil {
+		t.Fatalf("failed to parse test metadata: %v", err)
+	}
+	table := ret.Metadata.TableByName(name)
+	if table == nil {
+		t.Fatalf("table %s not in test metadata", name)
+	}
+	return table
+}
+
+const testRow = `{
+	"_id": "Qtf7xPmoRaiFyQPO1aENTjb",
+	"Name": "name1",
+	"age": 20,
+	"Birthday": "2021-03-05",
+	"Status": "222222",
+	"Tags": ["red", "444444"],
+	"Done": true,
+	"Owner": ["87d1ad3ffe4e4ec1ba8@auth.local"],
+	"Attachments": [{"name": "a.md", "size": 11, "type": "file", "url": "https://cloud.seatable.io/a.md"}],
+	"Photos": ["https://cloud.seatable.io/p.png"],
+	"Projects": [{"row_id": "Ab1", "display_value": "Launch"}],
+	"Place": {"lng": 13.4, "lat": 52.5},
+	"Score": 40,
+	"_mtime": "2021-03-05T09:31:12.123+00:00"
+}`
+
+func TestRowAccessors(t *testing.T) {
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(testRow), &values); err != nil {
+		t.Fatal(err)
+	}
+	row := NewRow(testTable(t, "table1"), values)
+
+	if s, err := row.String("Name"); err != nil || s != "name1" {
+		t.Errorf("String(Name) = %q, %v", s, err)
+	}
+	if s, err := row.String("Status"); err != nil || s != "Done" {
+		t.Errorf("String(Status) = %q, %v", s, err)
+	}
+	if f, err := row.Float("age"); err != nil || f != 20 {
+		t.Errorf("Float(age) = %v, %v", f, err)
+	}
+	if f, err := row.Float("Score"); err != nil || f != 40 {
+		t.Errorf("Float(Score) = %v, %v", f, err)
+	}
+	if d, err := row.Time("Birthday"); err != nil || !d.Equal(time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time(Birthday) = %v, %v", d, err)
+	}
+	if b, err := row.Bool("Done"); err != nil || !b {
+		t.Errorf("Bool(Done) = %v, %v", b, err)
+	}
+	if tags, err := row.Strings("Tags"); err != nil || len(tags) != 2 || tags[1] != "blue" {
+		t.Errorf("Strings(Tags) = %v, %v", tags, err)
+	}
+	if c, err := row.Collaborators("Owner"); err != nil || len(c) != 1 {
+		t.Errorf("Collaborators(Owner) = %v, %v", c, err)
+	}
+	if f, err := row.Files("Attachments"); err != nil || len(f) != 1 || f[0].Size != 11 {
+		t.Errorf("Files(Attachments) = %v, %v", f, err)
+	}
+	if i, err := row.Images("Photos"); err != nil || len(i) != 1 {
+		t.Errorf("Images(Photos) = %v, %v", i, err)
+	}
+	if l, err := row.Links("Projects"); err != nil || len(l) != 1 || l[0].RowID != "Ab1" {
+		t.Errorf("Links(Projects) = %v, %v", l, err)
+	}
+	if g, err := row.Geolocation("Place"); err != nil || g.Lat != 52.5 {
+		t.Errorf("Geolocation(Place) = %v, %v", g, err)
+	}
+
+	if _, err := row.Float("Name"); err == nil {
+		t.Errorf("expected error reading text column as number")
+	}
+	if _, err := row.String("Missing"); err == nil {
+		t.Errorf("expected error for unknown column")
+	}
+	delete(values, "age")
+	if _, err := row.Float("age"); err != ErrNoValue {
+		t.Errorf("expected ErrNoValue for empty number, got %v", err)
+	}
+}
+
+func TestGetRow(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMetadata))
+	})
+	for _, id := range []string{"r1", "r2"} {
+		id := id
+		si.handle("/rows/"+id+"/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("table_name") != "table1" {
+				t.Errorf("unexpected query %v", r.URL.Query())
+			}
+			writeJSON(w, map[string]interface{}{"_id": id, "Name": "name-" + id, "Status": "222222"})
+		})
+	}
+	si.handle("/rows/gone/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{})
+	})
+	b := si.base(t)
+
+	row, err := b.GetRow("table1", "r1")
+	if err != nil {
+		t.Fatalf("failed to get row: %v", err)
+	}
+	if s, _ := row.String("Status"); row.ID != "r1" || s != "Done" {
+		t.Errorf("unexpected row %+v", row)
+	}
+
+	for _, id := range []string{"missing", "gone"} {
+		_, err = b.GetRow("table1", id)
+		var notFound *RowNotFoundError
+		if !errors.As(err, &notFound) || !IsNotFound(err) || notFound.RowIDs[0] != id {
+			t.Errorf("%s: expected a RowNotFoundError, got %v", id, err)
+		}
+	}
+
+	if _, err := b.GetRow("missing", "r1"); !IsNotFound(err) {
+		t.Errorf("expected not found for a missing table, got %v", err)
+	}
+
+	// Without dtable-db GetRows falls back to the row endpoint.
+	b.DtableDbURL = ""
+	rows, err := b.GetRows("table1", []string{"r2", "missing", "r1"})
+	var notFound *RowNotFoundError
+	if !errors.As(err, &notFound) || len(notFound.RowIDs) != 1 {
+		t.Errorf("expected a RowNotFoundError, got %v", err)
+	}
+	if len(rows) != 2 || rows[0].ID != "r2" || rows[1].ID != "r1" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
+
+func TestGetRows(t *testing.T) {
+	var queries []string
+	si := newStandIn(t)
+	si.handle("/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMetadata))
+	})
+	si.handle("/dtable-db/api/v1/query/"+standInUUID+"/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			SQL        string   `json:"sql"`
+			Parameters []string `json:"parameters"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		queries = append(queries, req.SQL)
+
+		var rows []interface{}
+		for _, id := range req.Parameters {
+			if id != "missing" {
+				rows = append(rows, map[string]interface{}{"_id": id, "Name": "name-" + id, "Status": "222222"})
+			}
+		}
+		writeJSON(w, map[string]interface{}{"success": true, "results": rows})
+	})
+	b := si.base(t)
+
+	rows, err := b.GetRows("table1", []string{"r2", "missing", "r1"})
+	var notFound *RowNotFoundError
+	if !errors.As(err, &notFound) || len(notFound.RowIDs) != 1 || notFound.RowIDs[0] != "missing" {
+		t.Errorf("expected a RowNotFoundError, got %v", err)
+	}
+	if len(rows) != 2 || rows[0].ID != "r2" || rows[1].ID != "r1" {
+		t.Fatalf("unexpected rows %v", rows)
+	}
+	if s, _ := rows[0].String("Status"); s != "Done" {
+		t.Errorf("unexpected row %+v", rows[0])
+	}
+	if len(queries) != 1 || queries[0] != "SELECT * FROM `table1` WHERE _id IN (?, ?, ?) LIMIT 3" {
+		t.Errorf("unexpected queries %q", queries)
+	}
+
+	queries = nil
+	ids := make([]string, 1500)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("r%d", i)
+	}
+	rows, err = b.GetRows("table1", ids)
+	if err != nil || len(rows) != 1500 || rows[1499].ID != "r1499" {
+		t.Errorf("got %d rows, %v", len(rows), err)
+	}
+	if len(queries) != 2 {
+		t.Errorf("expected 2 queries, got %d", len(queries))
+	}
+}
@@ -2,6 +2,7 @@ package seatable_api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,13 +12,20 @@ import (
 	neturl "net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
+// Base is a client for a single SeaTable base and is safe for concurrent
+// use by multiple goroutines. Auth updates the credential fields under an
+// internal lock; callers should configure the exported fields before the
+// Base is shared and not write them afterwards. Use Clone to get an
+// independent set of credentials that reuses the same connections.
 type Base struct {
 	Token           string
 	ServerURL       string
 	DtableServerURL string
+	DtableDbURL     string
 	JwtToken        string
 	JwtExp          int64
 	Headers         map[string]string
@@ -26,24 +34,46 @@ type Base struct {
 	DtableName      string
 	Timeout         int
 	Client          *SocketIO
+
+	mu         sync.RWMutex
+	authMu     sync.Mutex
+	httpClient *http.Client
+	transport  http.RoundTripper
+	userAgent  string
+	retry      RetryPolicy
+	limits     *rateLimits
+	links      *linkCache
 }
 
-func Init(token string, serverURL string) *Base {
-	return &Base{Token: token, ServerURL: serverURL, Timeout: 30}
+func Init(token string, serverURL string, opts ...Option) *Base {
+	s := &Base{Token: token, ServerURL: serverURL, Timeout: 30, httpClient: &http.Client{}, links: newLinkCache()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.transport != nil {
+		c := *s.client()
+		c.Transport = s.transport
+		s.httpClient = &c
+	}
+	return s
 }
 
 func (s *Base) Auth(withSocketIO bool) error {
-	s.JwtExp = time.Now().Add(72 * time.Hour).Unix()
+	return s.AuthCtx(context.Background(), withSocketIO)
+}
+
+func (s *Base) AuthCtx(ctx context.Context, withSocketIO bool) error {
+	jwtExp := time.Now().Add(72 * time.Hour).Unix()
 	url := s.ServerURL + "/api/v2.1/dtable/app-access-token/"
 	Headers := makeHeaders(s.Token)
-	status, body, err := httpGet(url, "", Headers, s.Timeout, nil)
+	status, body, err := s.httpGet(ctx, url, "", Headers, nil)
 	if err != nil {
-		err := fmt.Errorf("failed to request url: %s: %v", url, err)
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
 		return err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for GET: %d", status)
+		err := newAPIError("GET", url, status, body)
 		return err
 	}
 
@@ -59,14 +89,21 @@ func (s *Base) Auth(withSocketIO bool) error {
 		return err
 	}
 
+	s.mu.Lock()
 	serverURL, ok := ret["dtable_server"].(string)
 	if ok {
 		s.DtableServerURL = parseServerURL(serverURL)
 	}
 
+	dbURL, ok := ret["dtable_db"].(string)
+	if ok {
+		s.DtableDbURL = parseServerURL(dbURL)
+	}
+
 	accessToken, ok := ret["access_token"].(string)
 	if ok {
 		s.JwtToken = accessToken
+		s.JwtExp = jwtExp
 		s.Headers = makeHeaders(accessToken)
 	}
 
@@ -84,6 +121,7 @@ func (s *Base) Auth(withSocketIO bool) error {
 	if ok {
 		s.DtableName = dtableName
 	}
+	s.mu.Unlock()
 
 	if withSocketIO {
 		base, err := s.Clone()
@@ -96,61 +134,101 @@ func (s *Base) Auth(withSocketIO bool) error {
 			err := fmt.Errorf("failed to init socket io: %v", err)
 			return err
 		}
+		s.mu.Lock()
 		s.Client = client
+		s.mu.Unlock()
 	}
 	return nil
 }
 
+// Clone returns a copy of the Base with its own credentials. The copy
+// shares the http client, retry policy, rate limiters and socket io
+// client with s.
 func (s *Base) Clone() (*Base, error) {
-	var dst = new(Base)
-	b, err := json.Marshal(s)
-	if err != nil {
-		return nil, err
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dst := &Base{
+		Token:           s.Token,
+		ServerURL:       s.ServerURL,
+		DtableServerURL: s.DtableServerURL,
+		DtableDbURL:     s.DtableDbURL,
+		JwtToken:        s.JwtToken,
+		JwtExp:          s.JwtExp,
+		WorkspaceID:     s.WorkspaceID,
+		DtableUUID:      s.DtableUUID,
+		DtableName:      s.DtableName,
+		Timeout:         s.Timeout,
+		Client:          s.Client,
+		httpClient:      s.httpClient,
+		userAgent:       s.userAgent,
+		retry:           s.retry,
+		limits:          s.limits,
+		links:           s.links,
+	}
+	if s.Headers != nil {
+		dst.Headers = make(map[string]string, len(s.Headers))
+		for k, v := range s.Headers {
+			dst.Headers[k] = v
+		}
 	}
-
-	err = json.Unmarshal(b, dst)
-	return dst, err
+	return dst, nil
 }
 
 func parseServerURL(serverURL string) string {
 	return strings.TrimRight(serverURL, "/")
 }
 
-func (s *Base) GetMetadata() (interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/metadata/"
+func (s *Base) GetMetadata() (*Metadata, error) {
+	return s.GetMetadataCtx(context.Background())
+}
+
+func (s *Base) GetMetadataCtx(ctx context.Context) (*Metadata, error) {
+	url := s.dtableURL("/metadata/")
 
-	status, body, err := httpGet(url, "", s.Headers, s.Timeout, nil)
+	status, body, err := s.httpGet(ctx, url, "", s.authHeaders(), nil)
 	if err != nil {
-		err := fmt.Errorf("failed to request url: %s: %v", url, err)
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for GET: %d", status)
+		err := newAPIError("GET", url, status, body)
 		return nil, err
 	}
 
-	rsp, err := parseResponse(body)
+	var ret struct {
+		Metadata *Metadata `json:"metadata"`
+	}
+	err = json.Unmarshal(body, &ret)
 	if err != nil {
 		err := fmt.Errorf("failed to parse response: %v", err)
 		return nil, err
 	}
 
-	ret, ok := rsp.(map[string]interface{})
-	if !ok {
-		err := fmt.Errorf("failed to assert response")
+	if ret.Metadata == nil {
+		err := fmt.Errorf("invalid response")
 		return nil, err
 	}
 
-	return ret["metadata"], nil
+	return ret.Metadata, nil
 }
 
 func (s *Base) AppendRow(tableName string, rowData interface{}) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/rows/"
+	return s.AppendRowCtx(context.Background(), tableName, rowData)
+}
+
+func (s *Base) AppendRowCtx(ctx context.Context, tableName string, rowData interface{}) (map[string]interface{}, error) {
+	url := s.dtableURL("/rows/")
+
+	row, err := toRowData(rowData)
+	if err != nil {
+		return nil, err
+	}
 
 	data := make(map[string]interface{})
 	data["table_name"] = tableName
-	data["row"] = rowData
+	data["row"] = row
 
 	jsonStr, err := json.Marshal(data)
 	if err != nil {
@@ -158,14 +236,14 @@ func (s *Base) AppendRow(tableName string, rowData interface{}) (map[string]inte
 		return nil, err
 	}
 
-	status, body, err := httpPost(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpPost(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("POST", url, status, body)
 		return nil, err
 	}
 
@@ -185,11 +263,26 @@ func (s *Base) AppendRow(tableName string, rowData interface{}) (map[string]inte
 }
 
 func (s *Base) BatchAppendRows(tableName string, rowsData []interface{}) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/batch-append-rows/"
+	return s.BatchAppendRowsCtx(context.Background(), tableName, rowsData)
+}
+
+// BatchAppendRowsCtx appends rows in requests of at most 1000 rows and
+// returns the merged responses. See BatchAppendRowsWithOptions for
+// parallel requests and per-request results.
+func (s *Base) BatchAppendRowsCtx(ctx context.Context, tableName string, rowsData []interface{}) (map[string]interface{}, error) {
+	ret, err := s.BatchAppendRowsWithOptionsCtx(ctx, tableName, rowsData, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ret.Response(), nil
+}
+
+func (s *Base) batchAppendRows(ctx context.Context, tableName string, rows []interface{}) (map[string]interface{}, error) {
+	url := s.dtableURL("/batch-append-rows/")
 
 	data := make(map[string]interface{})
 	data["table_name"] = tableName
-	data["rows"] = rowsData
+	data["rows"] = rows
 
 	jsonStr, err := json.Marshal(data)
 	if err != nil {
@@ -197,14 +290,14 @@ func (s *Base) BatchAppendRows(tableName string, rowsData []interface{}) (map[st
 		return nil, err
 	}
 
-	status, body, err := httpPost(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpPost(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post rows to %s: %v", url, err)
+		err := fmt.Errorf("failed to post rows to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("POST", url, status, body)
 		return nil, err
 	}
 
@@ -224,11 +317,20 @@ func (s *Base) BatchAppendRows(tableName string, rowsData []interface{}) (map[st
 }
 
 func (s *Base) InsertRow(tableName string, rowData interface{}, anchorRowID string) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/rows/"
+	return s.InsertRowCtx(context.Background(), tableName, rowData, anchorRowID)
+}
+
+func (s *Base) InsertRowCtx(ctx context.Context, tableName string, rowData interface{}, anchorRowID string) (map[string]interface{}, error) {
+	url := s.dtableURL("/rows/")
+
+	row, err := toRowData(rowData)
+	if err != nil {
+		return nil, err
+	}
 
 	data := make(map[string]interface{})
 	data["table_name"] = tableName
-	data["row"] = rowData
+	data["row"] = row
 	data["anchor_row_id"] = anchorRowID
 
 	jsonStr, err := json.Marshal(data)
@@ -237,14 +339,14 @@ func (s *Base) InsertRow(tableName string, rowData interface{}, anchorRowID stri
 		return nil, err
 	}
 
-	status, body, err := httpPost(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpPost(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("POST", url, status, body)
 		return nil, err
 	}
 
@@ -264,12 +366,21 @@ func (s *Base) InsertRow(tableName string, rowData interface{}, anchorRowID stri
 }
 
 func (s *Base) UpdateRow(tableName string, rowID string, rowData interface{}) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/rows/"
+	return s.UpdateRowCtx(context.Background(), tableName, rowID, rowData)
+}
+
+func (s *Base) UpdateRowCtx(ctx context.Context, tableName string, rowID string, rowData interface{}) (map[string]interface{}, error) {
+	url := s.dtableURL("/rows/")
+
+	row, err := toRowData(rowData)
+	if err != nil {
+		return nil, err
+	}
 
 	data := make(map[string]interface{})
 	data["table_name"] = tableName
 	data["row_id"] = rowID
-	data["row"] = rowData
+	data["row"] = row
 
 	jsonStr, err := json.Marshal(data)
 	if err != nil {
@@ -277,14 +388,14 @@ func (s *Base) UpdateRow(tableName string, rowID string, rowData interface{}) (m
 		return nil, err
 	}
 
-	status, body, err := httpPut(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpPut(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("PUT", url, status, body)
 		return nil, err
 	}
 
@@ -304,7 +415,11 @@ func (s *Base) UpdateRow(tableName string, rowID string, rowData interface{}) (m
 }
 
 func (s *Base) DeleteRow(tableName, rowID string) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/rows/"
+	return s.DeleteRowCtx(context.Background(), tableName, rowID)
+}
+
+func (s *Base) DeleteRowCtx(ctx context.Context, tableName, rowID string) (map[string]interface{}, error) {
+	url := s.dtableURL("/rows/")
 
 	data := make(map[string]interface{})
 	data["table_name"] = tableName
@@ -316,14 +431,14 @@ func (s *Base) DeleteRow(tableName, rowID string) (map[string]interface{}, error
 		return nil, err
 	}
 
-	status, body, err := httpDelete(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpDelete(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("DELETE", url, status, body)
 		return nil, err
 	}
 
@@ -343,7 +458,26 @@ func (s *Base) DeleteRow(tableName, rowID string) (map[string]interface{}, error
 }
 
 func (s *Base) BatchDeleteRows(tableName string, rowIDs interface{}) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/batch-delete-rows/"
+	return s.BatchDeleteRowsCtx(context.Background(), tableName, rowIDs)
+}
+
+// BatchDeleteRowsCtx deletes rows in requests of at most 1000 rows.
+// rowIDs is a []string or another slice of row ids.
+func (s *Base) BatchDeleteRowsCtx(ctx context.Context, tableName string, rowIDs interface{}) (map[string]interface{}, error) {
+	ids, err := toRowIDs(rowIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := s.BatchDeleteRowsWithOptionsCtx(ctx, tableName, ids, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ret.Response(), nil
+}
+
+func (s *Base) batchDeleteRows(ctx context.Context, tableName string, rowIDs []string) (map[string]interface{}, error) {
+	url := s.dtableURL("/batch-delete-rows/")
 
 	data := make(map[string]interface{})
 	data["table_name"] = tableName
@@ -355,14 +489,14 @@ func (s *Base) BatchDeleteRows(tableName string, rowIDs interface{}) (map[string
 		return nil, err
 	}
 
-	status, body, err := httpDelete(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpDelete(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("DELETE", url, status, body)
 		return nil, err
 	}
 
@@ -382,13 +516,51 @@ func (s *Base) BatchDeleteRows(tableName string, rowIDs interface{}) (map[string
 }
 
 func (s *Base) FilterRows(tableName string, filters []map[string]interface{}, viewName string, filterConjunction string) (interface{}, error) {
+	return s.FilterRowsCtx(context.Background(), tableName, filters, viewName, filterConjunction)
+}
+
+func (s *Base) FilterRowsCtx(ctx context.Context, tableName string, filters []map[string]interface{}, viewName string, filterConjunction string) (interface{}, error) {
+	params, jsonStr, err := filterRowsRequest(tableName, filters, viewName, filterConjunction)
+	if err != nil {
+		return nil, err
+	}
+
+	url := s.dtableURL("/filtered-rows/")
+
+	status, body, err := s.httpGet(ctx, url, params, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("GET", url, status, body)
+		return nil, err
+	}
+
+	rsp, err := parseResponse(body)
+	if err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+
+	ret, ok := rsp.(map[string]interface{})
+	if !ok {
+		err := fmt.Errorf("failed to assert response")
+		return nil, err
+	}
+
+	return ret["rows"], nil
+}
+
+func filterRowsRequest(tableName string, filters []map[string]interface{}, viewName string, filterConjunction string) (string, []byte, error) {
 	if filters == nil {
 		err := fmt.Errorf("filters can not be empty")
-		return nil, err
+		return "", nil, err
 	}
 
 	for _, v := range filters {
-		for k, _ := range v {
+		for k := range v {
 			hasKey := false
 			for _, key := range ROW_FILTER_KEYS {
 				if k == key {
@@ -398,18 +570,21 @@ func (s *Base) FilterRows(tableName string, filters []map[string]interface{}, vi
 			}
 			if !hasKey {
 				err := fmt.Errorf("filters invalid")
-				return nil, err
+				return "", nil, err
 			}
 		}
 	}
 
 	if filterConjunction != "And" && filterConjunction != "Or" {
 		err := fmt.Errorf("filter_conjunction invalid, filter_conjunction must be \"And\" or \"Or\"")
-		return nil, err
+		return "", nil, err
 	}
 
 	params := neturl.Values{}
 	params.Add("table_name", tableName)
+	if viewName != "" {
+		params.Add("view_name", viewName)
+	}
 
 	data := make(map[string]interface{})
 	data["filters"] = filters
@@ -418,51 +593,30 @@ func (s *Base) FilterRows(tableName string, filters []map[string]interface{}, vi
 	jsonStr, err := json.Marshal(data)
 	if err != nil {
 		err := fmt.Errorf("failed to encode json data: %v", err)
-		return nil, err
+		return "", nil, err
 	}
 
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/filtered-rows/"
-
-	status, body, err := httpGet(url, params.Encode(), s.Headers, s.Timeout, bytes.NewBuffer(jsonStr))
-	if err != nil {
-		err := fmt.Errorf("failed to request url: %s: %v", url, err)
-		return nil, err
-	}
-
-	if status >= 400 {
-		err := fmt.Errorf("bad response for GET: %d", status)
-		return nil, err
-	}
-
-	rsp, err := parseResponse(body)
-	if err != nil {
-		err := fmt.Errorf("failed to parse response: %v", err)
-		return nil, err
-	}
-
-	ret, ok := rsp.(map[string]interface{})
-	if !ok {
-		err := fmt.Errorf("failed to assert response")
-		return nil, err
-	}
-
-	return ret["rows"], nil
+	return params.Encode(), jsonStr, nil
 }
 
 func (s *Base) GetFileDownloadLink(path string) (interface{}, error) {
+	return s.GetFileDownloadLinkCtx(context.Background(), path)
+}
+
+func (s *Base) GetFileDownloadLinkCtx(ctx context.Context, path string) (interface{}, error) {
 	url := s.ServerURL + "/api/v2.1/dtable/app-download-link/"
 
 	params := neturl.Values{}
 	params.Add("path", path)
 
-	status, body, err := httpGet(url, params.Encode(), s.Headers, s.Timeout, nil)
+	status, body, err := s.httpGet(ctx, url, params.Encode(), s.authHeaders(), nil)
 	if err != nil {
-		err := fmt.Errorf("failed to request url: %s: %v", url, err)
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for GET: %d", status)
+		err := newAPIError("GET", url, status, body)
 		return nil, err
 	}
 
@@ -482,16 +636,20 @@ func (s *Base) GetFileDownloadLink(path string) (interface{}, error) {
 }
 
 func (s *Base) GetFileUploadLink() (map[string]interface{}, error) {
+	return s.GetFileUploadLinkCtx(context.Background())
+}
+
+func (s *Base) GetFileUploadLinkCtx(ctx context.Context) (map[string]interface{}, error) {
 	url := s.ServerURL + "/api/v2.1/dtable/app-upload-link/"
 
-	status, body, err := httpGet(url, "", s.Headers, s.Timeout, nil)
+	status, body, err := s.httpGet(ctx, url, "", s.authHeaders(), nil)
 	if err != nil {
-		err := fmt.Errorf("failed to request url: %s: %v", url, err)
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for GET: %d", status)
+		err := newAPIError("GET", url, status, body)
 		return nil, err
 	}
 
@@ -511,7 +669,11 @@ func (s *Base) GetFileUploadLink() (map[string]interface{}, error) {
 }
 
 func (s *Base) AddLink(linkID, tableName, otherTableName, rowID, otherRowID string) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/links/"
+	return s.AddLinkCtx(context.Background(), linkID, tableName, otherTableName, rowID, otherRowID)
+}
+
+func (s *Base) AddLinkCtx(ctx context.Context, linkID, tableName, otherTableName, rowID, otherRowID string) (map[string]interface{}, error) {
+	url := s.dtableURL("/links/")
 
 	data := make(map[string]interface{})
 	data["link_id"] = linkID
@@ -526,14 +688,14 @@ func (s *Base) AddLink(linkID, tableName, otherTableName, rowID, otherRowID stri
 		return nil, err
 	}
 
-	status, body, err := httpPost(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpPost(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("POST", url, status, body)
 		return nil, err
 	}
 
@@ -553,7 +715,11 @@ func (s *Base) AddLink(linkID, tableName, otherTableName, rowID, otherRowID stri
 }
 
 func (s *Base) RemoveLink(linkID, tableName, otherTableName, rowID, otherRowID string) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/links/"
+	return s.RemoveLinkCtx(context.Background(), linkID, tableName, otherTableName, rowID, otherRowID)
+}
+
+func (s *Base) RemoveLinkCtx(ctx context.Context, linkID, tableName, otherTableName, rowID, otherRowID string) (map[string]interface{}, error) {
+	url := s.dtableURL("/links/")
 
 	data := make(map[string]interface{})
 	data["link_id"] = linkID
@@ -568,14 +734,14 @@ func (s *Base) RemoveLink(linkID, tableName, otherTableName, rowID, otherRowID s
 		return nil, err
 	}
 
-	status, body, err := httpDelete(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpDelete(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("DELETE", url, status, body)
 		return nil, err
 	}
 
@@ -594,29 +760,45 @@ func (s *Base) RemoveLink(linkID, tableName, otherTableName, rowID, otherRowID s
 	return ret, nil
 }
 
-func (s *Base) GetColumnLinkID(tableName, columnName, viewName string) (interface{}, error) {
-	columns, err := s.ListColumns(tableName, viewName)
+func (s *Base) GetColumnLinkID(tableName, columnName, viewName string) (string, error) {
+	return s.GetColumnLinkIDCtx(context.Background(), tableName, columnName, viewName)
+}
+
+// GetColumnLinkIDCtx returns the link id of a link column, as needed by
+// AddLink and RemoveLink. Link ids are cached by the Base and its clones.
+func (s *Base) GetColumnLinkIDCtx(ctx context.Context, tableName, columnName, viewName string) (string, error) {
+	if linkID, ok := s.links.get(tableName, columnName); ok {
+		return linkID, nil
+	}
+
+	columns, err := s.ListColumnsCtx(ctx, tableName, viewName)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	lists, ok := columns.([]map[string]interface{})
-	if ok {
-		for _, column := range lists {
-			if column["name"] == columnName && column["type"] == "link" {
-				data, ok := column["data"].(map[string]interface{})
-				if ok {
-					return data["link_id"], nil
-				}
-			}
+	var list []*Column
+	if err := convertValue(columns, &list); err != nil {
+		err := fmt.Errorf("failed to decode columns: %v", err)
+		return "", err
+	}
+
+	for _, column := range list {
+		if column.Name == columnName && column.Type == LINK && column.Data != nil {
+			s.links.set(tableName, columnName, column.Data.LinkID)
+			return column.Data.LinkID, nil
 		}
 	}
 
-	return nil, nil
+	err = fmt.Errorf("link column %s in table %s: %w", columnName, tableName, ErrNotFound)
+	return "", err
 }
 
 func (s *Base) ListColumns(tableName, viewName string) (interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/columns/"
+	return s.ListColumnsCtx(context.Background(), tableName, viewName)
+}
+
+func (s *Base) ListColumnsCtx(ctx context.Context, tableName, viewName string) (interface{}, error) {
+	url := s.dtableURL("/columns/")
 
 	params := neturl.Values{}
 	params.Add("table_name", tableName)
@@ -624,14 +806,14 @@ func (s *Base) ListColumns(tableName, viewName string) (interface{}, error) {
 		params.Add("view_name", viewName)
 	}
 
-	status, body, err := httpGet(url, params.Encode(), s.Headers, s.Timeout, nil)
+	status, body, err := s.httpGet(ctx, url, params.Encode(), s.authHeaders(), nil)
 	if err != nil {
-		err := fmt.Errorf("failed to request url: %s: %v", url, err)
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for GET: %d", status)
+		err := newAPIError("GET", url, status, body)
 		return nil, err
 	}
 
@@ -651,7 +833,31 @@ func (s *Base) ListColumns(tableName, viewName string) (interface{}, error) {
 }
 
 func (s *Base) InsertColumn(tableName, columnName string, columnType ColumnTypes, columnKey string) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/columns/"
+	return s.InsertColumnCtx(context.Background(), tableName, columnName, columnType, columnKey)
+}
+
+func (s *Base) InsertColumnCtx(ctx context.Context, tableName, columnName string, columnType ColumnTypes, columnKey string) (map[string]interface{}, error) {
+	return s.InsertColumnWithDataCtx(ctx, tableName, columnName, columnType, columnKey, nil)
+}
+
+// InsertColumnWithData adds a column with type specific settings.
+// columnData may be nil or the column data type matching columnType, e.g.
+// NumberColumnData for a NUMBER column.
+func (s *Base) InsertColumnWithData(tableName, columnName string, columnType ColumnTypes, columnKey string, columnData TypedColumnData) (map[string]interface{}, error) {
+	return s.InsertColumnWithDataCtx(context.Background(), tableName, columnName, columnType, columnKey, columnData)
+}
+
+func (s *Base) InsertColumnWithDataCtx(ctx context.Context, tableName, columnName string, columnType ColumnTypes, columnKey string, columnData TypedColumnData) (map[string]interface{}, error) {
+	if !columnType.IsValid() {
+		err := fmt.Errorf("column type %q invalid", columnType)
+		return nil, err
+	}
+	if columnData != nil && !columnData.validFor(columnType) {
+		err := fmt.Errorf("column data %T invalid for column type %s", columnData, columnType)
+		return nil, err
+	}
+
+	url := s.dtableURL("/columns/")
 
 	data := make(map[string]interface{})
 	data["table_name"] = tableName
@@ -660,6 +866,9 @@ func (s *Base) InsertColumn(tableName, columnName string, columnType ColumnTypes
 	if columnKey != "" {
 		data["column_key"] = columnKey
 	}
+	if columnData != nil {
+		data["column_data"] = columnData
+	}
 
 	jsonStr, err := json.Marshal(data)
 	if err != nil {
@@ -667,14 +876,14 @@ func (s *Base) InsertColumn(tableName, columnName string, columnType ColumnTypes
 		return nil, err
 	}
 
-	status, body, err := httpPost(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpPost(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("POST", url, status, body)
 		return nil, err
 	}
 
@@ -694,7 +903,11 @@ func (s *Base) InsertColumn(tableName, columnName string, columnType ColumnTypes
 }
 
 func (s *Base) RenameColumn(tableName, columnKey, newColumnName string) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/columns/"
+	return s.RenameColumnCtx(context.Background(), tableName, columnKey, newColumnName)
+}
+
+func (s *Base) RenameColumnCtx(ctx context.Context, tableName, columnKey, newColumnName string) (map[string]interface{}, error) {
+	url := s.dtableURL("/columns/")
 
 	data := make(map[string]interface{})
 	data["op_type"] = RENAME_COLUMN
@@ -708,17 +921,19 @@ func (s *Base) RenameColumn(tableName, columnKey, newColumnName string) (map[str
 		return nil, err
 	}
 
-	status, body, err := httpPut(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpPut(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("PUT", url, status, body)
 		return nil, err
 	}
 
+	s.links.reset()
+
 	rsp, err := parseResponse(body)
 	if err != nil {
 		err := fmt.Errorf("failed to parse response: %v", err)
@@ -735,7 +950,11 @@ func (s *Base) RenameColumn(tableName, columnKey, newColumnName string) (map[str
 }
 
 func (s *Base) ResizeColumn(tableName, columnKey string, newColumnWidth int) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/columns/"
+	return s.ResizeColumnCtx(context.Background(), tableName, columnKey, newColumnWidth)
+}
+
+func (s *Base) ResizeColumnCtx(ctx context.Context, tableName, columnKey string, newColumnWidth int) (map[string]interface{}, error) {
+	url := s.dtableURL("/columns/")
 
 	data := make(map[string]interface{})
 	data["op_type"] = RESIZE_COLUMN
@@ -749,14 +968,14 @@ func (s *Base) ResizeColumn(tableName, columnKey string, newColumnWidth int) (ma
 		return nil, err
 	}
 
-	status, body, err := httpPut(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpPut(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("PUT", url, status, body)
 		return nil, err
 	}
 
@@ -776,7 +995,11 @@ func (s *Base) ResizeColumn(tableName, columnKey string, newColumnWidth int) (ma
 }
 
 func (s *Base) FreezeColumn(tableName, columnKey string, frozen bool) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/columns/"
+	return s.FreezeColumnCtx(context.Background(), tableName, columnKey, frozen)
+}
+
+func (s *Base) FreezeColumnCtx(ctx context.Context, tableName, columnKey string, frozen bool) (map[string]interface{}, error) {
+	url := s.dtableURL("/columns/")
 
 	data := make(map[string]interface{})
 	data["op_type"] = FREEZE_COLUMN
@@ -790,14 +1013,14 @@ func (s *Base) FreezeColumn(tableName, columnKey string, frozen bool) (map[strin
 		return nil, err
 	}
 
-	status, body, err := httpPut(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpPut(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("PUT", url, status, body)
 		return nil, err
 	}
 
@@ -816,8 +1039,13 @@ func (s *Base) FreezeColumn(tableName, columnKey string, frozen bool) (map[strin
 	return ret, nil
 }
 
-func (s *Base) moveColumn(tableName, columnKey string, targetColumnKey bool) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/columns/"
+func (s *Base) MoveColumn(tableName, columnKey, targetColumnKey string) (map[string]interface{}, error) {
+	return s.MoveColumnCtx(context.Background(), tableName, columnKey, targetColumnKey)
+}
+
+// MoveColumnCtx moves a column next to the column targetColumnKey.
+func (s *Base) MoveColumnCtx(ctx context.Context, tableName, columnKey, targetColumnKey string) (map[string]interface{}, error) {
+	url := s.dtableURL("/columns/")
 
 	data := make(map[string]interface{})
 	data["op_type"] = MOVE_COLUMN
@@ -831,14 +1059,14 @@ func (s *Base) moveColumn(tableName, columnKey string, targetColumnKey bool) (ma
 		return nil, err
 	}
 
-	status, body, err := httpPut(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpPut(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("PUT", url, status, body)
 		return nil, err
 	}
 
@@ -858,7 +1086,16 @@ func (s *Base) moveColumn(tableName, columnKey string, targetColumnKey bool) (ma
 }
 
 func (s *Base) ModifyColumnType(tableName, columnKey string, newColumnType ColumnTypes) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/columns/"
+	return s.ModifyColumnTypeCtx(context.Background(), tableName, columnKey, newColumnType)
+}
+
+func (s *Base) ModifyColumnTypeCtx(ctx context.Context, tableName, columnKey string, newColumnType ColumnTypes) (map[string]interface{}, error) {
+	if !newColumnType.IsValid() {
+		err := fmt.Errorf("column type %q invalid", newColumnType)
+		return nil, err
+	}
+
+	url := s.dtableURL("/columns/")
 
 	data := make(map[string]interface{})
 	data["op_type"] = MODIFY_COLUMN_TYPE
@@ -872,17 +1109,19 @@ func (s *Base) ModifyColumnType(tableName, columnKey string, newColumnType Colum
 		return nil, err
 	}
 
-	status, body, err := httpPut(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpPut(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("PUT", url, status, body)
 		return nil, err
 	}
 
+	s.links.reset()
+
 	rsp, err := parseResponse(body)
 	if err != nil {
 		err := fmt.Errorf("failed to parse response: %v", err)
@@ -899,7 +1138,11 @@ func (s *Base) ModifyColumnType(tableName, columnKey string, newColumnType Colum
 }
 
 func (s *Base) DeleteColumn(tableName, columnKey string) (map[string]interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/columns/"
+	return s.DeleteColumnCtx(context.Background(), tableName, columnKey)
+}
+
+func (s *Base) DeleteColumnCtx(ctx context.Context, tableName, columnKey string) (map[string]interface{}, error) {
+	url := s.dtableURL("/columns/")
 
 	data := make(map[string]interface{})
 	data["table_name"] = tableName
@@ -911,17 +1154,19 @@ func (s *Base) DeleteColumn(tableName, columnKey string) (map[string]interface{}
 		return nil, err
 	}
 
-	status, body, err := httpDelete(url, s.Headers, bytes.NewBuffer(jsonStr), s.Timeout)
+	status, body, err := s.httpDelete(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", url, err)
+		err := fmt.Errorf("failed to post row to %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("DELETE", url, status, body)
 		return nil, err
 	}
 
+	s.links.reset()
+
 	rsp, err := parseResponse(body)
 	if err != nil {
 		err := fmt.Errorf("failed to parse response: %v", err)
@@ -938,12 +1183,17 @@ func (s *Base) DeleteColumn(tableName, columnKey string) (map[string]interface{}
 }
 
 func (s *Base) DownloadFile(url, savePath string) error {
-	if strings.Index(url, s.DtableUUID) < 0 {
+	return s.DownloadFileCtx(context.Background(), url, savePath)
+}
+
+func (s *Base) DownloadFileCtx(ctx context.Context, url, savePath string) error {
+	dtableUUID := s.dtableUUID()
+	if strings.Index(url, dtableUUID) < 0 {
 		err := fmt.Errorf("url invalid")
 		return err
 	}
 
-	paths := strings.Split(url, s.DtableUUID)
+	paths := strings.Split(url, dtableUUID)
 	path := strings.Trim(paths[len(paths)-1], "/")
 
 	unescapePath, err := neturl.PathUnescape(path)
@@ -951,7 +1201,7 @@ func (s *Base) DownloadFile(url, savePath string) error {
 		return err
 	}
 
-	downloadLink, err := s.GetFileDownloadLink(unescapePath)
+	downloadLink, err := s.GetFileDownloadLinkCtx(ctx, unescapePath)
 	if err != nil {
 		return err
 	}
@@ -961,14 +1211,14 @@ func (s *Base) DownloadFile(url, savePath string) error {
 		err := fmt.Errorf("failed to assert download link")
 		return err
 	}
-	status, body, err := httpGet(url, "", s.Headers, s.Timeout, nil)
+	status, body, err := s.httpGet(ctx, url, "", s.authHeaders(), nil)
 	if err != nil {
-		err := fmt.Errorf("failed to request url: %s: %v", url, err)
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
 		return err
 	}
 
 	if status != 200 {
-		err := fmt.Errorf("download file error")
+		err := newAPIError("GET", url, status, body)
 		return err
 	}
 
@@ -989,9 +1239,13 @@ func (s *Base) DownloadFile(url, savePath string) error {
 }
 
 func (s *Base) UploadBytesFile(name string, r io.Reader, relativePath, fileType string, replace bool) (map[string]interface{}, error) {
-	uploadLinkDict, err := s.GetFileUploadLink()
+	return s.UploadBytesFileCtx(context.Background(), name, r, relativePath, fileType, replace)
+}
+
+func (s *Base) UploadBytesFileCtx(ctx context.Context, name string, r io.Reader, relativePath, fileType string, replace bool) (map[string]interface{}, error) {
+	uploadLinkDict, err := s.GetFileUploadLinkCtx(ctx)
 	if err != nil {
-		err := fmt.Errorf("failed to get file upload link: %v", err)
+		err := fmt.Errorf("failed to get file upload link: %w", err)
 		return nil, err
 	}
 
@@ -1027,20 +1281,17 @@ func (s *Base) UploadBytesFile(name string, r io.Reader, relativePath, fileType
 		return nil, err
 	}
 
-	headers := make(map[string]string)
-	for k, v := range s.Headers {
-		headers[k] = v
-	}
+	headers := s.authHeaders()
 	headers["Content-Type"] = contentType
 
-	status, body, err := httpPost(uploadLink, headers, form, s.Timeout)
+	status, body, err := s.httpPost(ctx, uploadLink, headers, form)
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", uploadLink, err)
+		err := fmt.Errorf("failed to post row to %s: %w", uploadLink, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("POST", uploadLink, status, body)
 		return nil, err
 	}
 
@@ -1083,8 +1334,8 @@ func (s *Base) UploadBytesFile(name string, r io.Reader, relativePath, fileType
 	}
 
 	url := fmt.Sprintf("%s/workspace/%s/asset/%s/%s/%s",
-		strings.Trim(s.ServerURL, "/"), s.WorkspaceID,
-		s.DtableUUID, path, rowName)
+		strings.Trim(s.ServerURL, "/"), s.workspaceID(),
+		s.dtableUUID(), path, rowName)
 
 	ret := make(map[string]interface{})
 	ret["type"] = fileType
@@ -1096,6 +1347,10 @@ func (s *Base) UploadBytesFile(name string, r io.Reader, relativePath, fileType
 }
 
 func (s *Base) UploadLocalFile(filePath, name, relativePath, fileType string, replace bool) (map[string]interface{}, error) {
+	return s.UploadLocalFileCtx(context.Background(), filePath, name, relativePath, fileType, replace)
+}
+
+func (s *Base) UploadLocalFileCtx(ctx context.Context, filePath, name, relativePath, fileType string, replace bool) (map[string]interface{}, error) {
 	if fileType != "image" && fileType != "file" {
 		err := fmt.Errorf("file_type invalid")
 		return nil, err
@@ -1120,9 +1375,9 @@ func (s *Base) UploadLocalFile(filePath, name, relativePath, fileType string, re
 		relativePath = strings.Trim(relativePath, "/")
 	}
 
-	uploadLinkDict, err := s.GetFileUploadLink()
+	uploadLinkDict, err := s.GetFileUploadLinkCtx(ctx)
 	if err != nil {
-		err := fmt.Errorf("failed to get file upload link: %v", err)
+		err := fmt.Errorf("failed to get file upload link: %w", err)
 		return nil, err
 	}
 
@@ -1152,20 +1407,17 @@ func (s *Base) UploadLocalFile(filePath, name, relativePath, fileType string, re
 		return nil, err
 	}
 
-	headers := make(map[string]string)
-	for k, v := range s.Headers {
-		headers[k] = v
-	}
+	headers := s.authHeaders()
 	headers["Content-Type"] = contentType
 
-	status, body, err := httpPost(uploadLink, headers, form, s.Timeout)
+	status, body, err := s.httpPost(ctx, uploadLink, headers, form)
 	if err != nil {
-		err := fmt.Errorf("failed to post row to %s: %v", uploadLink, err)
+		err := fmt.Errorf("failed to post row to %s: %w", uploadLink, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for POST: %d", status)
+		err := newAPIError("POST", uploadLink, status, body)
 		return nil, err
 	}
 
@@ -1208,8 +1460,8 @@ func (s *Base) UploadLocalFile(filePath, name, relativePath, fileType string, re
 	}
 
 	url := fmt.Sprintf("%s/workspace/%s/asset/%s/%s/%s",
-		strings.Trim(s.ServerURL, "/"), s.WorkspaceID,
-		s.DtableUUID, path, rowName)
+		strings.Trim(s.ServerURL, "/"), s.workspaceID(),
+		s.dtableUUID(), path, rowName)
 
 	ret := make(map[string]interface{})
 	ret["type"] = fileType
@@ -1220,47 +1472,78 @@ func (s *Base) UploadLocalFile(filePath, name, relativePath, fileType string, re
 	return ret, nil
 }
 
-/*
+// Filter lists the rows of a table and returns the rows matching
+// conditions, see the conditions syntax in conditions.go. An empty
+// condition string matches all rows.
 func (s *Base) Filter(tableName, conditions, viewName string) (*QuerySet, error) {
+	return s.FilterCtx(context.Background(), tableName, conditions, viewName)
+}
+
+func (s *Base) FilterCtx(ctx context.Context, tableName, conditions, viewName string) (*QuerySet, error) {
 	var err error
 	base, err := s.Clone()
 	if err != nil {
 		err := fmt.Errorf("failed to clone base: %v", err)
 		return nil, err
 	}
-	queryset := NewQuerySet(base, tableName)
-	queryset.RawRows, err = s.ListRows(tableName, viewName)
+
+	rawColumns, err := s.ListColumnsCtx(ctx, tableName, viewName)
 	if err != nil {
 		return nil, err
 	}
-	queryset.RawColumns, err = s.ListColumns(tableName, viewName)
-	if err != nil {
+	var columns []*Column
+	if err := convertValue(rawColumns, &columns); err != nil {
+		err := fmt.Errorf("failed to decode columns: %v", err)
+		return nil, err
+	}
+
+	queryset := NewQuerySet(base, &Table{Name: tableName, Columns: columns})
+	it := s.IterateRowsCtx(ctx, tableName, viewName, nil)
+	it.table = queryset.table
+	for it.Next() {
+		queryset.Rows = append(queryset.Rows, it.Row())
+	}
+	if err := it.Err(); err != nil {
 		return nil, err
 	}
 	queryset.Conditions = conditions
-	queryset.ExecuteConditions()
+	if err := queryset.ExecuteConditions(); err != nil {
+		return nil, err
+	}
 
 	return queryset, nil
 }
-*/
 
 func (s *Base) ListRows(tableName, viewName string) (interface{}, error) {
-	url := s.DtableServerURL + "/api/v1/dtables/" + s.DtableUUID + "/rows/"
+	return s.ListRowsCtx(context.Background(), tableName, viewName)
+}
+
+func (s *Base) ListRowsCtx(ctx context.Context, tableName, viewName string) (interface{}, error) {
+	return s.ListRowsWithOptionsCtx(ctx, tableName, viewName, nil)
+}
+
+func (s *Base) ListRowsWithOptions(tableName, viewName string, opts *ListRowsOptions) (interface{}, error) {
+	return s.ListRowsWithOptionsCtx(context.Background(), tableName, viewName, opts)
+}
+
+func (s *Base) ListRowsWithOptionsCtx(ctx context.Context, tableName, viewName string, opts *ListRowsOptions) (interface{}, error) {
+	url := s.dtableURL("/rows/")
 
 	params := neturl.Values{}
 	params.Add("table_name", tableName)
 	if viewName != "" {
 		params.Add("view_name", viewName)
 	}
+	opts.encode(params)
 
-	status, body, err := httpGet(url, params.Encode(), s.Headers, s.Timeout, nil)
+	status, body, err := s.httpGet(ctx, url, params.Encode(), s.authHeaders(), nil)
 	if err != nil {
-		err := fmt.Errorf("failed to request url: %s: %v", url, err)
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
 		return nil, err
 	}
 
 	if status >= 400 {
-		err := fmt.Errorf("bad response for GET: %d", status)
+		err := newAPIError("GET", url, status, body)
 		return nil, err
 	}
 
@@ -1323,8 +1606,8 @@ func parseResponse(body []byte) (interface{}, error) {
 	return data, nil
 }
 
-func httpGet(url, params string, headers map[string]string, timeout int, body io.Reader) (int, []byte, error) {
-	req, err := http.NewRequest("GET", url, body)
+func (s *Base) httpGet(ctx context.Context, url, params string, headers map[string]string, body io.Reader) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, body)
 	if err != nil {
 		err := fmt.Errorf("failed to create http GET request: %v", err)
 		return 0, nil, err
@@ -1334,62 +1617,188 @@ func httpGet(url, params string, headers map[string]string, timeout int, body io
 		req.URL.RawQuery = params
 	}
 
-	return httpCommon(req, headers, timeout)
+	return s.httpCommon(req, headers)
 }
 
-func httpPost(url string, headers map[string]string, body io.Reader, timeout int) (int, []byte, error) {
-	req, err := http.NewRequest("POST", url, body)
+func (s *Base) httpPost(ctx context.Context, url string, headers map[string]string, body io.Reader) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		err := fmt.Errorf("failed to create http POST request: %v", err)
 		return 0, nil, err
 	}
 
-	return httpCommon(req, headers, timeout)
+	return s.httpCommon(req, headers)
 }
 
-func httpPut(url string, headers map[string]string, body io.Reader, timeout int) (int, []byte, error) {
-	req, err := http.NewRequest("PUT", url, body)
+func (s *Base) httpPut(ctx context.Context, url string, headers map[string]string, body io.Reader) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, body)
 	if err != nil {
 		err := fmt.Errorf("failed to create http POST request: %v", err)
 		return 0, nil, err
 	}
 
-	return httpCommon(req, headers, timeout)
+	return s.httpCommon(req, headers)
 }
 
-func httpDelete(url string, headers map[string]string, body io.Reader, timeout int) (int, []byte, error) {
-	req, err := http.NewRequest("DELETE", url, body)
+func (s *Base) httpDelete(ctx context.Context, url string, headers map[string]string, body io.Reader) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, body)
 	if err != nil {
 		err := fmt.Errorf("failed to create http POST request: %v", err)
 		return 0, nil, err
 	}
 
-	return httpCommon(req, headers, timeout)
+	return s.httpCommon(req, headers)
+}
+
+func (s *Base) httpCommon(req *http.Request, headers map[string]string) (int, []byte, error) {
+	rsp, err := s.roundTrip(req, headers, false)
+	if rsp == nil {
+		return 0, nil, err
+	}
+	defer rsp.Body.Close()
+
+	if err != nil {
+		return rsp.StatusCode, nil, err
+	}
+
+	body, err := ioutil.ReadAll(rsp.Body)
+	return rsp.StatusCode, body, err
 }
 
-func httpCommon(req *http.Request, headers map[string]string, timeout int) (int, []byte, error) {
+// roundTrip sends req, waiting for the rate limiter, refreshing the access
+// token and retrying as configured. Unless stream is set the response
+// body has been read into memory when roundTrip returns; either way the
+// caller must close it.
+func (s *Base) roundTrip(req *http.Request, headers map[string]string, stream bool) (*http.Response, error) {
 	for k, v := range headers {
 		req.Header.Add(k, v)
 	}
+	if s.userAgent != "" {
+		req.Header.Set("User-Agent", s.userAgent)
+	}
 
-	var client *http.Client
-	if timeout > 0 {
-		client = &http.Client{Timeout: time.Duration(timeout) * time.Second}
-	} else {
-		client = &http.Client{}
+	jwtAuth := s.isJwtAuth(headers)
+	if jwtAuth && s.jwtExpired() {
+		if err := s.refreshAuth(req.Context(), req); err != nil {
+			return nil, err
+		}
 	}
 
-	rsp, err := client.Do(req)
+	reauthed := false
+	for attempt := 1; ; attempt++ {
+		if err := s.waitRateLimit(req.Context(), req.URL.String()); err != nil {
+			return nil, err
+		}
+
+		rsp, err := s.doRequest(req, stream)
+		var status int
+		var header http.Header
+		if rsp != nil {
+			status, header = rsp.StatusCode, rsp.Header
+		}
+
+		if err == nil && status == http.StatusUnauthorized && jwtAuth && !reauthed {
+			reauthed = true
+			rsp.Body.Close()
+			req, err = rewindRequest(req)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.refreshAuth(req.Context(), req); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		wait, retry := s.retry.shouldRetry(req, attempt, status, header, err)
+		if !retry {
+			return rsp, err
+		}
+		if rsp != nil {
+			rsp.Body.Close()
+		}
+
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		req, err = rewindRequest(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *Base) doRequest(req *http.Request, stream bool) (*http.Response, error) {
+	if stream {
+		return s.doStreamRequest(req)
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if s.Timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), time.Duration(s.Timeout)*time.Second)
+		req = req.WithContext(ctx)
+	}
+	defer cancel()
+
+	rsp, err := s.client().Do(req)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 	defer rsp.Body.Close()
 
 	body, err := ioutil.ReadAll(rsp.Body)
 	if err != nil {
 		err := fmt.Errorf("failed to read from response body: %v", err)
-		return rsp.StatusCode, nil, err
+		rsp.Body = http.NoBody
+		return rsp, err
 	}
+	rsp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return rsp, nil
+}
+
+// doStreamRequest sends a request whose response body is read by the
+// caller. Timeout only bounds the wait for the response headers; reading
+// the body, which may take much longer for a large table, is bounded by
+// the request context alone.
+func (s *Base) doStreamRequest(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	req = req.WithContext(ctx)
+
+	stop := func() bool { return true }
+	if s.Timeout > 0 {
+		stop = time.AfterFunc(time.Duration(s.Timeout)*time.Second, cancel).Stop
+	}
+
+	rsp, err := s.client().Do(req)
+	if !stop() {
+		if rsp != nil {
+			rsp.Body.Close()
+		}
+		cancel()
+		err := fmt.Errorf("timeout awaiting response headers: %w", context.DeadlineExceeded)
+		return nil, err
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	rsp.Body = &cancelReadCloser{ReadCloser: rsp.Body, cancel: cancel}
+	return rsp, nil
+}
+
+// cancelReadCloser releases the request context once a streamed response
+// body is closed.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
 
-	return rsp.StatusCode, body, nil
+func (c *cancelReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
 }
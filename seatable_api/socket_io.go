@@ -17,7 +17,7 @@ type Message struct {
 }
 
 func InitSocketIO(base *Base) (*SocketIO, error) {
-	url := base.DtableServerURL + "?dtable_uuid=" + base.DtableUUID
+	url := base.dtableServerURL() + "?dtable_uuid=" + base.dtableUUID()
 
 	c, err := gosocketio.Dial(
 		url,
@@ -32,7 +32,7 @@ func InitSocketIO(base *Base) (*SocketIO, error) {
 
 func (sio *SocketIO) Connect() error {
 	err := sio.Client.On(gosocketio.OnConnection, func(c *gosocketio.Channel) {
-		if time.Now().Unix() >= sio.Base.JwtExp {
+		if sio.Base.jwtExpired() {
 			err := sio.Base.Auth(false)
 			if err != nil {
 				err := fmt.Errorf("failed to auth: %v", err)
@@ -41,8 +41,8 @@ func (sio *SocketIO) Connect() error {
 			fmt.Println(time.Now(), "[ SeaTable SocketIO JWT token refreshed ]")
 		}
 		var data []string
-		data = append(data, sio.Base.DtableUUID)
-		data = append(data, sio.Base.JwtToken)
+		data = append(data, sio.Base.dtableUUID())
+		data = append(data, sio.Base.jwtToken())
 		c.Emit(JOIN_ROOM, data)
 		fmt.Println(time.Now(), "[ SeaTable SocketIO connection established ]")
 	})
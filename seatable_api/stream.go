@@ -0,0 +1,135 @@
+package seatable_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+)
+
+// maxErrorBody bounds how much of an error response is read when
+// streaming.
+const maxErrorBody = 64 << 10
+
+// StreamRows decodes the rows of a table one at a time and passes each
+// to fn, so that large tables are processed in bounded memory. Iteration
+// stops at the first error returned by fn, which StreamRows returns.
+func (s *Base) StreamRows(tableName, viewName string, opts *ListRowsOptions, fn func(row map[string]interface{}) error) error {
+	return s.StreamRowsCtx(context.Background(), tableName, viewName, opts, fn)
+}
+
+func (s *Base) StreamRowsCtx(ctx context.Context, tableName, viewName string, opts *ListRowsOptions, fn func(row map[string]interface{}) error) error {
+	url := s.dtableURL("/rows/")
+
+	params := neturl.Values{}
+	params.Add("table_name", tableName)
+	if viewName != "" {
+		params.Add("view_name", viewName)
+	}
+	opts.encode(params)
+
+	return s.streamGet(ctx, url, params.Encode(), nil, fn)
+}
+
+func (s *Base) StreamFilteredRows(tableName string, filters []map[string]interface{}, viewName string, filterConjunction string, fn func(row map[string]interface{}) error) error {
+	return s.StreamFilteredRowsCtx(context.Background(), tableName, filters, viewName, filterConjunction, fn)
+}
+
+func (s *Base) StreamFilteredRowsCtx(ctx context.Context, tableName string, filters []map[string]interface{}, viewName string, filterConjunction string, fn func(row map[string]interface{}) error) error {
+	params, jsonStr, err := filterRowsRequest(tableName, filters, viewName, filterConjunction)
+	if err != nil {
+		return err
+	}
+
+	url := s.dtableURL("/filtered-rows/")
+	return s.streamGet(ctx, url, params, bytes.NewBuffer(jsonStr), fn)
+}
+
+func (s *Base) streamGet(ctx context.Context, url, params string, body io.Reader, fn func(row map[string]interface{}) error) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, body)
+	if err != nil {
+		err := fmt.Errorf("failed to create http GET request: %v", err)
+		return err
+	}
+	if params != "" {
+		req.URL.RawQuery = params
+	}
+
+	rsp, err := s.roundTrip(req, s.authHeaders(), true)
+	if rsp != nil {
+		defer rsp.Body.Close()
+	}
+	if err != nil {
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
+		return err
+	}
+
+	if rsp.StatusCode >= 400 {
+		body, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, maxErrorBody))
+		err := newAPIError("GET", url, rsp.StatusCode, body)
+		return err
+	}
+
+	return decodeRowsStream(rsp.Body, fn)
+}
+
+// decodeRowsStream reads a JSON object and calls fn for every element of
+// its "rows" array without holding the whole array in memory.
+func decodeRowsStream(r io.Reader, fn func(row map[string]interface{}) error) error {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			err := fmt.Errorf("failed to parse response: %v", err)
+			return err
+		}
+
+		if key, _ := tok.(string); key != "rows" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				err := fmt.Errorf("failed to parse response: %v", err)
+				return err
+			}
+			continue
+		}
+
+		if err := expectDelim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			var row map[string]interface{}
+			if err := dec.Decode(&row); err != nil {
+				err := fmt.Errorf("failed to parse row: %v", err)
+				return err
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		err := fmt.Errorf("failed to parse response: expect %v, got %v", delim, tok)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package seatable_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeRowsStream(t *testing.T) {
+	body := `{"rows": [{"_id": "a", "age": 1}, {"_id": "b", "age": 2}, {"_id": "c"}], "other": {"x": [1, 2]}}`
+
+	var ids []string
+	err := decodeRowsStream(strings.NewReader(body), func(row map[string]interface{}) error {
+		ids = append(ids, row["_id"].(string))
+		return nil
+	})
+	if err != nil || strings.Join(ids, ",") != "a,b,c" {
+		t.Errorf("decoded %v, %v", ids, err)
+	}
+
+	stop := errors.New("stop")
+	n := 0
+	err = decodeRowsStream(strings.NewReader(body), func(row map[string]interface{}) error {
+		n++
+		return stop
+	})
+	if err != stop || n != 1 {
+		t.Errorf("callback error not returned: %v after %d rows", err, n)
+	}
+
+	if err := decodeRowsStream(strings.NewReader(`{"rows": [{"_id": `), func(map[string]interface{}) error { return nil }); err == nil {
+		t.Errorf("expected error for truncated response")
+	}
+}
+
+func TestStreamFilteredRows(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/filtered-rows/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"rows": [`))
+		for i := 0; i < 1000; i++ {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"_id": "row%d", "Name": "name%d"}`, i, i)
+		}
+		w.Write([]byte(`]}`))
+	})
+	b := si.base(t)
+
+	filters := []map[string]interface{}{{"column_name": "Name", "filter_predicate": "contains", "filter_term": "name"}}
+	n := 0
+	err := b.StreamFilteredRows(tableName, filters, "", "And", func(row map[string]interface{}) error {
+		n++
+		return nil
+	})
+	if err != nil || n != 1000 {
+		t.Errorf("streamed %d rows, %v", n, err)
+	}
+
+	err = b.StreamRows("missing", "", nil, func(map[string]interface{}) error { return nil })
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestStreamRowsSlowerThanTimeout(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"rows": [`))
+		for i := 0; i < 6; i++ {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"_id": "row%d"}`, i)
+			w.(http.Flusher).Flush()
+			time.Sleep(300 * time.Millisecond)
+		}
+		w.Write([]byte(`]}`))
+	})
+	b := si.base(t)
+	b.Timeout = 1
+
+	n := 0
+	err := b.StreamRows(tableName, "", nil, func(map[string]interface{}) error {
+		n++
+		return nil
+	})
+	if err != nil || n != 6 {
+		t.Errorf("streamed %d rows, %v", n, err)
+	}
+
+	// The timeout still applies to the response headers.
+	si.handle("/rows/", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(3 * time.Second):
+		}
+	})
+	b = si.base(t, WithRetryPolicy(RetryPolicy{}))
+	b.Timeout = 1
+	err = b.StreamRows(tableName, "", nil, func(map[string]interface{}) error { return nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
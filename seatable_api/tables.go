@@ -0,0 +1,199 @@
+package seatable_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// TableColumn is a column of a table created by AddTable. Data may be
+// nil or the column data type matching Type, as for InsertColumnWithData.
+type TableColumn struct {
+	Name string
+	Type ColumnTypes
+	Data TypedColumnData
+}
+
+// AddTable creates a table. lang sets the language of the default column
+// and view names and defaults to "en". columns may be nil, in which case
+// the table gets a single text column.
+func (s *Base) AddTable(tableName, lang string, columns []*TableColumn) (*Table, error) {
+	return s.AddTableCtx(context.Background(), tableName, lang, columns)
+}
+
+func (s *Base) AddTableCtx(ctx context.Context, tableName, lang string, columns []*TableColumn) (*Table, error) {
+	url := s.dtableURL("/tables/")
+
+	if lang == "" {
+		lang = "en"
+	}
+
+	data := make(map[string]interface{})
+	data["table_name"] = tableName
+	data["lang"] = lang
+	if len(columns) > 0 {
+		list := make([]map[string]interface{}, 0, len(columns))
+		for _, c := range columns {
+			if !c.Type.IsValid() {
+				err := fmt.Errorf("column type %q of column %s invalid", c.Type, c.Name)
+				return nil, err
+			}
+			if c.Data != nil && !c.Data.validFor(c.Type) {
+				err := fmt.Errorf("column data %T of column %s invalid for column type %s", c.Data, c.Name, c.Type)
+				return nil, err
+			}
+			column := make(map[string]interface{})
+			column["column_name"] = c.Name
+			column["column_type"] = c.Type
+			if c.Data != nil {
+				column["column_data"] = c.Data
+			}
+			list = append(list, column)
+		}
+		data["columns"] = list
+	}
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		err := fmt.Errorf("failed to encode post data: %v", err)
+		return nil, err
+	}
+
+	status, body, err := s.httpPost(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to post table to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("POST", url, status, body)
+		return nil, err
+	}
+
+	return s.tableResponse(ctx, body, tableName)
+}
+
+func (s *Base) RenameTable(tableName, newTableName string) (*Table, error) {
+	return s.RenameTableCtx(context.Background(), tableName, newTableName)
+}
+
+func (s *Base) RenameTableCtx(ctx context.Context, tableName, newTableName string) (*Table, error) {
+	url := s.dtableURL("/tables/")
+
+	data := make(map[string]interface{})
+	data["table_name"] = tableName
+	data["new_table_name"] = newTableName
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		err := fmt.Errorf("failed to encode put data: %v", err)
+		return nil, err
+	}
+
+	status, body, err := s.httpPut(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to post table to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("PUT", url, status, body)
+		return nil, err
+	}
+
+	s.links.reset()
+
+	return s.tableResponse(ctx, body, newTableName)
+}
+
+func (s *Base) DeleteTable(tableName string) (map[string]interface{}, error) {
+	return s.DeleteTableCtx(context.Background(), tableName)
+}
+
+func (s *Base) DeleteTableCtx(ctx context.Context, tableName string) (map[string]interface{}, error) {
+	url := s.dtableURL("/tables/")
+
+	data := make(map[string]interface{})
+	data["table_name"] = tableName
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		err := fmt.Errorf("failed to encode json data: %v", err)
+		return nil, err
+	}
+
+	status, body, err := s.httpDelete(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to post table to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("DELETE", url, status, body)
+		return nil, err
+	}
+
+	s.links.reset()
+
+	rsp, err := parseResponse(body)
+	if err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+
+	ret, ok := rsp.(map[string]interface{})
+	if !ok {
+		err := fmt.Errorf("failed to assert response")
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// DuplicateTable copies a table, including its rows if withRows is set.
+// The copy is named by the server, usually "<name> (copy)".
+func (s *Base) DuplicateTable(tableName string, withRows bool) (*Table, error) {
+	return s.DuplicateTableCtx(context.Background(), tableName, withRows)
+}
+
+func (s *Base) DuplicateTableCtx(ctx context.Context, tableName string, withRows bool) (*Table, error) {
+	url := s.dtableURL("/tables/duplicate-table/")
+
+	data := make(map[string]interface{})
+	data["table_name"] = tableName
+	data["is_duplicate_records"] = withRows
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		err := fmt.Errorf("failed to encode post data: %v", err)
+		return nil, err
+	}
+
+	status, body, err := s.httpPost(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to post table to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("POST", url, status, body)
+		return nil, err
+	}
+
+	return s.tableResponse(ctx, body, "")
+}
+
+// tableResponse decodes the table returned by the tables endpoints. When
+// the response holds no table, the table is looked up in the metadata.
+func (s *Base) tableResponse(ctx context.Context, body []byte, tableName string) (*Table, error) {
+	table := new(Table)
+	if err := json.Unmarshal(body, table); err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+	if table.ID != "" || tableName == "" {
+		return table, nil
+	}
+	return s.GetTableCtx(ctx, tableName)
+}
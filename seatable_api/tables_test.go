@@ -0,0 +1,84 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestTables(t *testing.T) {
+	si := newStandIn(t)
+	si.handle("/tables/", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		switch r.Method {
+		case http.MethodPost:
+			columns, _ := req["columns"].([]interface{})
+			if req["lang"] != "en" || len(columns) != 2 {
+				t.Errorf("unexpected request %v", req)
+			}
+			age, _ := columns[1].(map[string]interface{})
+			if data, _ := age["column_data"].(map[string]interface{}); age["column_type"] != "number" || data["format"] != "dollar" {
+				t.Errorf("unexpected column %v", age)
+			}
+			writeJSON(w, map[string]interface{}{
+				"_id": "t1", "name": req["table_name"],
+				"columns": []interface{}{
+					map[string]interface{}{"key": "0000", "name": "Name", "type": "text"},
+					map[string]interface{}{"key": "a1b2", "name": "age", "type": "number"},
+				},
+			})
+		case http.MethodPut:
+			if req["table_name"] != "table1" || req["new_table_name"] != "Projects" {
+				t.Errorf("unexpected request %v", req)
+			}
+			writeJSON(w, map[string]interface{}{"success": true})
+		case http.MethodDelete:
+			writeJSON(w, map[string]interface{}{"success": true})
+		}
+	})
+	si.handle("/tables/duplicate-table/", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["is_duplicate_records"] != true {
+			t.Errorf("unexpected request %v", req)
+		}
+		writeJSON(w, map[string]interface{}{"_id": "t2", "name": "table1 (copy)"})
+	})
+	si.handle("/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMetadata))
+	})
+	b := si.base(t)
+
+	table, err := b.AddTable("Customers", "", []*TableColumn{
+		{Name: "Name", Type: TEXT},
+		{Name: "age", Type: NUMBER, Data: &NumberColumnData{Format: NUMBER_FORMAT_DOLLAR}},
+	})
+	if err != nil {
+		t.Fatalf("failed to add table: %v", err)
+	}
+	if table.ID != "t1" || table.Name != "Customers" || table.ColumnByName("age") == nil {
+		t.Errorf("unexpected table %+v", table)
+	}
+
+	if _, err := b.AddTable("Bad", "", []*TableColumn{{Name: "x", Type: "bogus"}}); err == nil {
+		t.Error("expected an error for an invalid column type")
+	}
+	if _, err := b.AddTable("Bad", "", []*TableColumn{{Name: "x", Type: TEXT, Data: &DateColumnData{}}}); err == nil {
+		t.Error("expected an error for date data on a text column")
+	}
+
+	table, err = b.RenameTable("table1", "Projects")
+	if err != nil || table.ID != "9Xyz" {
+		t.Errorf("rename = %+v, %v", table, err)
+	}
+
+	table, err = b.DuplicateTable("table1", true)
+	if err != nil || table.Name != "table1 (copy)" {
+		t.Errorf("duplicate = %+v, %v", table, err)
+	}
+
+	if _, err := b.DeleteTable("table1"); err != nil {
+		t.Errorf("failed to delete table: %v", err)
+	}
+}
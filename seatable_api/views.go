@@ -0,0 +1,293 @@
+package seatable_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	neturl "net/url"
+)
+
+func (s *Base) viewURL(tableName, viewName string) string {
+	path := "/views/"
+	if viewName != "" {
+		path += neturl.PathEscape(viewName) + "/"
+	}
+
+	params := neturl.Values{}
+	params.Add("table_name", tableName)
+	return s.dtableURL(path) + "?" + params.Encode()
+}
+
+func decodeView(body []byte) (*View, error) {
+	view := new(View)
+	if err := json.Unmarshal(body, view); err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+	return view, nil
+}
+
+func (s *Base) ListViews(tableName string) ([]*View, error) {
+	return s.ListViewsCtx(context.Background(), tableName)
+}
+
+func (s *Base) ListViewsCtx(ctx context.Context, tableName string) ([]*View, error) {
+	url := s.viewURL(tableName, "")
+
+	status, body, err := s.httpGet(ctx, url, "", s.authHeaders(), nil)
+	if err != nil {
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("GET", url, status, body)
+		return nil, err
+	}
+
+	var ret struct {
+		Views []*View `json:"views"`
+	}
+	if err := json.Unmarshal(body, &ret); err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+
+	return ret.Views, nil
+}
+
+func (s *Base) GetView(tableName, viewName string) (*View, error) {
+	return s.GetViewCtx(context.Background(), tableName, viewName)
+}
+
+func (s *Base) GetViewCtx(ctx context.Context, tableName, viewName string) (*View, error) {
+	url := s.viewURL(tableName, viewName)
+
+	status, body, err := s.httpGet(ctx, url, "", s.authHeaders(), nil)
+	if err != nil {
+		err := fmt.Errorf("failed to request url: %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("GET", url, status, body)
+		return nil, err
+	}
+
+	return decodeView(body)
+}
+
+// AddView adds a table view without filters, sorts or groups; use
+// UpdateView to configure it.
+func (s *Base) AddView(tableName, viewName string) (*View, error) {
+	return s.AddViewCtx(context.Background(), tableName, viewName)
+}
+
+func (s *Base) AddViewCtx(ctx context.Context, tableName, viewName string) (*View, error) {
+	url := s.viewURL(tableName, "")
+
+	data := make(map[string]interface{})
+	data["name"] = viewName
+	data["type"] = "table"
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		err := fmt.Errorf("failed to encode post data: %v", err)
+		return nil, err
+	}
+
+	status, body, err := s.httpPost(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to post view to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("POST", url, status, body)
+		return nil, err
+	}
+
+	return decodeView(body)
+}
+
+func (s *Base) RenameView(tableName, viewName, newViewName string) (*View, error) {
+	return s.RenameViewCtx(context.Background(), tableName, viewName, newViewName)
+}
+
+func (s *Base) RenameViewCtx(ctx context.Context, tableName, viewName, newViewName string) (*View, error) {
+	data := make(map[string]interface{})
+	data["name"] = newViewName
+	return s.updateView(ctx, tableName, viewName, data)
+}
+
+func (s *Base) DeleteView(tableName, viewName string) (map[string]interface{}, error) {
+	return s.DeleteViewCtx(context.Background(), tableName, viewName)
+}
+
+func (s *Base) DeleteViewCtx(ctx context.Context, tableName, viewName string) (map[string]interface{}, error) {
+	url := s.viewURL(tableName, viewName)
+
+	status, body, err := s.httpDelete(ctx, url, s.authHeaders(), nil)
+	if err != nil {
+		err := fmt.Errorf("failed to post view to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("DELETE", url, status, body)
+		return nil, err
+	}
+
+	rsp, err := parseResponse(body)
+	if err != nil {
+		err := fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
+	}
+
+	ret, ok := rsp.(map[string]interface{})
+	if !ok {
+		err := fmt.Errorf("failed to assert response")
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// ViewSort sorts or groups a view by a column, given by name or key.
+type ViewSort struct {
+	Column     string
+	Descending bool
+}
+
+// ViewUpdate changes the configuration of a view. Nil fields are left
+// unchanged; an empty &Filters{} or an empty slice removes all filters,
+// sorts, groups or hidden columns. Columns are given by name or key.
+type ViewUpdate struct {
+	Filters       *Filters
+	Sorts         []ViewSort
+	Groupbys      []ViewSort
+	HiddenColumns []string
+	IsLocked      *bool
+}
+
+// UpdateView changes the filters, sorts, groups or hidden columns of a
+// view. They are checked against the table metadata first.
+func (s *Base) UpdateView(tableName, viewName string, update *ViewUpdate) (*View, error) {
+	return s.UpdateViewCtx(context.Background(), tableName, viewName, update)
+}
+
+func (s *Base) UpdateViewCtx(ctx context.Context, tableName, viewName string, update *ViewUpdate) (*View, error) {
+	if update == nil {
+		err := fmt.Errorf("view update can not be empty")
+		return nil, err
+	}
+
+	table, err := s.GetTableCtx(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := update.encode(table)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateView(ctx, tableName, viewName, data)
+}
+
+func (u *ViewUpdate) encode(table *Table) (map[string]interface{}, error) {
+	columnKey := func(name string) (string, error) {
+		col := table.ColumnByName(name)
+		if col == nil {
+			col = table.ColumnByKey(name)
+		}
+		if col == nil {
+			err := fmt.Errorf("column %s not found in table %s", name, table.Name)
+			return "", err
+		}
+		return col.Key, nil
+	}
+
+	sorts := func(list []ViewSort) ([]map[string]interface{}, error) {
+		ret := make([]map[string]interface{}, 0, len(list))
+		for _, v := range list {
+			key, err := columnKey(v.Column)
+			if err != nil {
+				return nil, err
+			}
+			sortType := "up"
+			if v.Descending {
+				sortType = "down"
+			}
+			ret = append(ret, map[string]interface{}{"column_key": key, "sort_type": sortType})
+		}
+		return ret, nil
+	}
+
+	data := make(map[string]interface{})
+	if u.Filters != nil {
+		filters, err := u.Filters.Build(table)
+		if err != nil {
+			return nil, err
+		}
+		// Views refer to columns by key.
+		for _, f := range filters {
+			f["column_key"] = table.ColumnByName(f["column_name"].(string)).Key
+			delete(f, "column_name")
+		}
+		data["filters"] = filters
+		data["filter_conjunction"] = u.Filters.Conjunction()
+	}
+	if u.Sorts != nil {
+		list, err := sorts(u.Sorts)
+		if err != nil {
+			return nil, err
+		}
+		data["sorts"] = list
+	}
+	if u.Groupbys != nil {
+		list, err := sorts(u.Groupbys)
+		if err != nil {
+			return nil, err
+		}
+		data["groupbys"] = list
+	}
+	if u.HiddenColumns != nil {
+		keys := make([]string, 0, len(u.HiddenColumns))
+		for _, name := range u.HiddenColumns {
+			key, err := columnKey(name)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+		data["hidden_columns"] = keys
+	}
+	if u.IsLocked != nil {
+		data["is_locked"] = *u.IsLocked
+	}
+	return data, nil
+}
+
+func (s *Base) updateView(ctx context.Context, tableName, viewName string, data map[string]interface{}) (*View, error) {
+	url := s.viewURL(tableName, viewName)
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		err := fmt.Errorf("failed to encode put data: %v", err)
+		return nil, err
+	}
+
+	status, body, err := s.httpPut(ctx, url, s.authHeaders(), bytes.NewBuffer(jsonStr))
+	if err != nil {
+		err := fmt.Errorf("failed to post view to %s: %w", url, err)
+		return nil, err
+	}
+
+	if status >= 400 {
+		err := newAPIError("PUT", url, status, body)
+		return nil, err
+	}
+
+	return decodeView(body)
+}
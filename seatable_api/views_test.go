@@ -0,0 +1,99 @@
+package seatable_api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestViews(t *testing.T) {
+	view := map[string]interface{}{"_id": "0000", "name": "Default View", "type": "table"}
+	var update map[string]interface{}
+
+	si := newStandIn(t)
+	si.handle("/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMetadata))
+	})
+	si.handle("/views/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("table_name") != "table1" {
+			t.Errorf("unexpected query %v", r.URL.Query())
+		}
+		if r.Method == http.MethodPost {
+			var req map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&req)
+			writeJSON(w, map[string]interface{}{"_id": "v2", "name": req["name"], "type": "table"})
+			return
+		}
+		writeJSON(w, map[string]interface{}{"views": []interface{}{view}})
+	})
+	si.handle("/views/Default View/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			update = nil
+			json.NewDecoder(r.Body).Decode(&update)
+			ret := make(map[string]interface{})
+			for k, v := range view {
+				ret[k] = v
+			}
+			for k, v := range update {
+				ret[k] = v
+			}
+			writeJSON(w, ret)
+		case http.MethodDelete:
+			writeJSON(w, map[string]interface{}{"success": true})
+		default:
+			writeJSON(w, view)
+		}
+	})
+	b := si.base(t)
+
+	views, err := b.ListViews("table1")
+	if err != nil || len(views) != 1 || views[0].Name != "Default View" {
+		t.Fatalf("list views = %v, %v", views, err)
+	}
+	if v, err := b.GetView("table1", "Default View"); err != nil || v.ID != "0000" {
+		t.Errorf("get view = %+v, %v", v, err)
+	}
+	if _, err := b.GetView("table1", "Missing"); !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if v, err := b.AddView("table1", "Team A"); err != nil || v.Name != "Team A" {
+		t.Errorf("add view = %+v, %v", v, err)
+	}
+	if v, err := b.RenameView("table1", "Default View", "All"); err != nil || v.Name != "All" {
+		t.Errorf("rename view = %+v, %v", v, err)
+	}
+
+	v, err := b.UpdateView("table1", "Default View", &ViewUpdate{
+		Filters:       Filter.Column("Status").Is("Open"),
+		Sorts:         []ViewSort{{Column: "age", Descending: true}},
+		Groupbys:      []ViewSort{{Column: "Status"}},
+		HiddenColumns: []string{"Photos"},
+	})
+	if err != nil {
+		t.Fatalf("failed to update view: %v", err)
+	}
+	want := map[string]interface{}{
+		"filters": []interface{}{map[string]interface{}{
+			"column_key": "e5f6", "filter_predicate": "is", "filter_term": "111111",
+		}},
+		"filter_conjunction": "And",
+		"sorts":              []interface{}{map[string]interface{}{"column_key": "a1b2", "sort_type": "down"}},
+		"groupbys":           []interface{}{map[string]interface{}{"column_key": "e5f6", "sort_type": "up"}},
+		"hidden_columns":     []interface{}{"o5p6"},
+	}
+	if !reflect.DeepEqual(update, want) {
+		t.Errorf("got update %v\nwant %v", update, want)
+	}
+	if len(v.Sorts) != 1 || len(v.HiddenColumns) != 1 {
+		t.Errorf("unexpected view %+v", v)
+	}
+
+	if _, err := b.UpdateView("table1", "Default View", &ViewUpdate{HiddenColumns: []string{"Missing"}}); err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+	if _, err := b.DeleteView("table1", "Default View"); err != nil {
+		t.Errorf("failed to delete view: %v", err)
+	}
+}